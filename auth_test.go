@@ -0,0 +1,124 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+func newAuthTestClient(t *testing.T, authenticator xsoar.ClientOption, handler http.HandlerFunc) *xsoar.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := xsoar.NewClient(xsoar.WithBaseURL(server.URL), authenticator)
+	require.NoError(t, err)
+
+	return client
+}
+
+func writeIncident(t *testing.T, w http.ResponseWriter) {
+	t.Helper()
+	err := json.NewEncoder(w).Encode(xsoar.Incident{ID: "inc-1"})
+	assert.NoError(t, err)
+}
+
+func TestAuthenticator(t *testing.T) {
+	t.Run("custom authenticator is called per request", func(t *testing.T) {
+		calls := 0
+		authFn := xsoar.AuthenticatorFunc(func(ctx context.Context, req *http.Request) error {
+			calls++
+			req.AddCookie(&http.Cookie{Name: "XSRF-TOKEN", Value: "csrf"})
+			req.Header.Set("X-XSRF-TOKEN", "csrf")
+			return nil
+		})
+
+		client := newAuthTestClient(t, xsoar.WithAuthenticator(authFn), func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "csrf", r.Header.Get("X-XSRF-TOKEN"))
+			cookie, err := r.Cookie("XSRF-TOKEN")
+			assert.NoError(t, err)
+			assert.Equal(t, "csrf", cookie.Value)
+			writeIncident(t, w)
+		})
+
+		ctx := context.Background()
+		for range 2 {
+			_, err := client.Incidents.Get(ctx, "inc-1")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("authenticator error aborts request", func(t *testing.T) {
+		secretErr := errors.New("secret store unavailable")
+		authFn := xsoar.AuthenticatorFunc(func(ctx context.Context, req *http.Request) error {
+			return secretErr
+		})
+
+		client := newAuthTestClient(t, xsoar.WithAuthenticator(authFn), func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call when authentication fails")
+		})
+
+		_, err := client.Incidents.Get(context.Background(), "inc-1")
+		require.ErrorIs(t, err, secretErr)
+	})
+
+	t.Run("nil authenticator", func(t *testing.T) {
+		_, err := xsoar.NewClient(
+			xsoar.WithBaseURL("https://api.xsoar.example.com"),
+			xsoar.WithAuthenticator(nil),
+		)
+		require.ErrorIs(t, err, xsoar.ErrNoCredentials)
+	})
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	t.Run("legacy key sends only Authorization", func(t *testing.T) {
+		client := newAuthTestClient(t, xsoar.WithLegacyAPIKey("legacy-key"), func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "legacy-key", r.Header.Get("Authorization"))
+			assert.Empty(t, r.Header.Get("x-xdr-auth-id"))
+			writeIncident(t, w)
+		})
+
+		_, err := client.Incidents.Get(context.Background(), "inc-1")
+		require.NoError(t, err)
+	})
+
+	t.Run("rotation applies to subsequent requests", func(t *testing.T) {
+		var keys []string
+		authenticator := xsoar.NewStandardKeyAuthenticator("key-1", "secret-1")
+
+		client := newAuthTestClient(t, xsoar.WithAuthenticator(authenticator), func(w http.ResponseWriter, r *http.Request) {
+			keys = append(keys, r.Header.Get("x-xdr-auth-id")+":"+r.Header.Get("Authorization"))
+			writeIncident(t, w)
+		})
+
+		ctx := context.Background()
+		_, err := client.Incidents.Get(ctx, "inc-1")
+		require.NoError(t, err)
+
+		authenticator.Rotate("key-2", "secret-2")
+		_, err = client.Incidents.Get(ctx, "inc-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"key-1:secret-1", "key-2:secret-2"}, keys)
+	})
+
+	t.Run("rotation to empty key fails requests", func(t *testing.T) {
+		authenticator := xsoar.NewStandardKeyAuthenticator("key-1", "secret-1")
+		client := newAuthTestClient(t, xsoar.WithAuthenticator(authenticator), func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call without credentials")
+		})
+
+		authenticator.Rotate("", "")
+		_, err := client.Incidents.Get(context.Background(), "inc-1")
+		require.ErrorIs(t, err, xsoar.ErrNoCredentials)
+	})
+}
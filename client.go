@@ -24,7 +24,6 @@ import (
 	"time"
 
 	"github.com/tphakala/go-xsoar/internal/api"
-	"github.com/tphakala/go-xsoar/internal/auth"
 )
 
 // Default configuration values.
@@ -35,6 +34,40 @@ type Client struct {
 	// Incidents provides access to incident operations.
 	Incidents IncidentService
 
+	// Entries provides access to War Room entry operations.
+	Entries EntryService
+
+	// Investigations provides access to investigation operations,
+	// such as running commands in the War Room.
+	Investigations InvestigationService
+
+	// Indicators provides access to threat intel indicator operations.
+	Indicators IndicatorService
+
+	// Tasks provides access to playbook tasks in investigation work plans.
+	Tasks TaskService
+
+	// Playbooks provides access to playbook definitions.
+	Playbooks PlaybookService
+
+	// Lists provides access to XSOAR lists.
+	Lists ListService
+
+	// Scripts provides access to automation scripts.
+	Scripts ScriptService
+
+	// Integrations provides access to integrations and their instances.
+	Integrations IntegrationService
+
+	// IncidentTypes provides access to incident type definitions.
+	IncidentTypes IncidentTypeService
+
+	// IncidentFields provides access to incident field definitions.
+	IncidentFields IncidentFieldService
+
+	// Layouts provides access to incident and indicator layouts.
+	Layouts LayoutService
+
 	transport *api.Transport
 }
 
@@ -52,13 +85,11 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 		return nil, ErrNoBaseURL
 	}
 
-	if cfg.keyID == "" || cfg.apiKey == "" {
+	if cfg.authenticator == nil {
 		return nil, ErrNoCredentials
 	}
-
-	creds := &auth.Credentials{
-		KeyID:  cfg.keyID,
-		APIKey: cfg.apiKey,
+	if keyAuth, ok := cfg.authenticator.(*APIKeyAuthenticator); ok && !keyAuth.valid() {
+		return nil, ErrNoCredentials
 	}
 
 	httpClient := cfg.httpClient
@@ -68,7 +99,7 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 		}
 	}
 
-	transport, err := api.NewTransport(cfg.baseURL, creds, httpClient)
+	transport, err := api.NewTransport(cfg.baseURL, cfg.authenticator, httpClient)
 	if err != nil {
 		return nil, err
 	}
@@ -76,13 +107,33 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 	if cfg.userAgent != "" {
 		transport.UserAgent = cfg.userAgent
 	}
+	transport.Retry = cfg.retry.toAPI()
+	if cfg.maxBodySize > 0 {
+		transport.MaxBodySize = cfg.maxBodySize
+	}
 
 	client := &Client{
 		transport: transport,
 	}
 
 	// Initialize services
-	client.Incidents = newIncidentService(transport)
+	incidents := newIncidentService(transport)
+	client.Incidents = incidents
+	client.Entries = newEntryService(transport)
+	client.Investigations = newInvestigationService(transport, client.Entries)
+	client.Indicators = newIndicatorService(transport)
+	client.Tasks = newTaskService(transport)
+	client.Playbooks = newPlaybookService(transport)
+	client.Lists = newListService(transport)
+	client.Scripts = newScriptService(transport)
+	client.Integrations = newIntegrationService(transport)
+	client.IncidentTypes = newIncidentTypeService(transport)
+	client.IncidentFields = newIncidentFieldService(transport)
+	client.Layouts = newLayoutService(transport)
+
+	if cfg.fieldValidation {
+		incidents.fields = newFieldValidator(client.IncidentFields, cfg.fieldCacheTTL)
+	}
 
 	return client, nil
 }
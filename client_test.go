@@ -1,7 +1,12 @@
 package xsoar_test
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -19,6 +24,17 @@ func TestNewClient(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotNil(t, client)
 		assert.NotNil(t, client.Incidents)
+		assert.NotNil(t, client.Entries)
+		assert.NotNil(t, client.Investigations)
+		assert.NotNil(t, client.Indicators)
+		assert.NotNil(t, client.Tasks)
+		assert.NotNil(t, client.Playbooks)
+		assert.NotNil(t, client.Lists)
+		assert.NotNil(t, client.Scripts)
+		assert.NotNil(t, client.Integrations)
+		assert.NotNil(t, client.IncidentTypes)
+		assert.NotNil(t, client.IncidentFields)
+		assert.NotNil(t, client.Layouts)
 		assert.Equal(t, "https://api.xsoar.example.com", client.BaseURL())
 	})
 
@@ -47,6 +63,16 @@ func TestNewClient(t *testing.T) {
 		assert.ErrorIs(t, err, xsoar.ErrNoCredentials)
 	})
 
+	t.Run("error with nil API key authenticator", func(t *testing.T) {
+		var keyAuth *xsoar.APIKeyAuthenticator
+		_, err := xsoar.NewClient(
+			xsoar.WithBaseURL("https://api.xsoar.example.com"),
+			xsoar.WithAuthenticator(keyAuth),
+		)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, xsoar.ErrNoCredentials)
+	})
+
 	t.Run("success with all options", func(t *testing.T) {
 		client, err := xsoar.NewClient(
 			xsoar.WithBaseURL("https://api.xsoar.example.com"),
@@ -80,3 +106,48 @@ func TestNewClient(t *testing.T) {
 		assert.NotNil(t, client)
 	})
 }
+
+func TestAdvancedAPIKey(t *testing.T) {
+	t.Run("error with partial credentials", func(t *testing.T) {
+		_, err := xsoar.NewClient(
+			xsoar.WithBaseURL("https://api.xsoar.example.com"),
+			xsoar.WithAdvancedAPIKey("", "api-key"),
+		)
+		require.ErrorIs(t, err, xsoar.ErrNoCredentials)
+	})
+
+	t.Run("signs each request with a fresh nonce", func(t *testing.T) {
+		var nonces []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nonce := r.Header.Get("x-xdr-nonce")
+			timestamp := r.Header.Get("x-xdr-timestamp")
+
+			assert.Equal(t, "key-id", r.Header.Get("x-xdr-auth-id"))
+			assert.Len(t, nonce, 64)
+			assert.NotEmpty(t, timestamp)
+
+			sum := sha256.Sum256([]byte("api-key" + nonce + timestamp))
+			assert.Equal(t, hex.EncodeToString(sum[:]), r.Header.Get("Authorization"))
+
+			nonces = append(nonces, nonce)
+			err := json.NewEncoder(w).Encode(xsoar.Incident{ID: "inc-1"})
+			assert.NoError(t, err)
+		}))
+		t.Cleanup(server.Close)
+
+		client, err := xsoar.NewClient(
+			xsoar.WithBaseURL(server.URL),
+			xsoar.WithAdvancedAPIKey("key-id", "api-key"),
+		)
+		require.NoError(t, err)
+
+		ctx := context.Background()
+		for range 2 {
+			_, err = client.Incidents.Get(ctx, "inc-1")
+			require.NoError(t, err)
+		}
+
+		require.Len(t, nonces, 2)
+		assert.NotEqual(t, nonces[0], nonces[1])
+	})
+}
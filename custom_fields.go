@@ -0,0 +1,217 @@
+package xsoar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultFieldCacheTTL is how long incident field definitions loaded
+	// for custom field validation are reused.
+	defaultFieldCacheTTL = 15 * time.Minute
+
+	// minFieldRefresh limits how often unknown field names trigger an early
+	// reload of the definitions, so that fields created after the cache was
+	// loaded are found without reloading on every typo.
+	minFieldRefresh = time.Minute
+)
+
+// fieldValidator checks CustomFields against the tenant's incident field
+// definitions before a request is sent.
+type fieldValidator struct {
+	fields IncidentFieldService
+	ttl    time.Duration
+
+	mu     sync.Mutex
+	byName map[string]*IncidentField
+	loaded time.Time
+}
+
+func newFieldValidator(fields IncidentFieldService, ttl time.Duration) *fieldValidator {
+	if ttl <= 0 {
+		ttl = defaultFieldCacheTTL
+	}
+	return &fieldValidator{fields: fields, ttl: ttl}
+}
+
+// validate checks each custom field value. It returns a ValidationError
+// whose Fields map names every invalid key.
+func (v *fieldValidator) validate(ctx context.Context, values map[string]any, opts []RequestOption) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if time.Since(v.loaded) >= v.ttl {
+		if err := v.load(ctx, opts); err != nil {
+			return err
+		}
+	}
+
+	invalid, unknown := v.check(values)
+	if unknown && time.Since(v.loaded) >= minFieldRefresh {
+		if err := v.load(ctx, opts); err != nil {
+			return err
+		}
+		invalid, _ = v.check(values)
+	}
+
+	if len(invalid) > 0 {
+		return &ValidationError{
+			APIError: APIError{Message: "invalid custom fields"},
+			Fields:   invalid,
+		}
+	}
+	return nil
+}
+
+// load replaces the cached definitions with the incident fields of the tenant.
+func (v *fieldValidator) load(ctx context.Context, opts []RequestOption) error {
+	fields, err := v.fields.List(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("loading incident fields: %w", err)
+	}
+
+	v.byName = make(map[string]*IncidentField, len(fields))
+	for _, field := range fields {
+		if field.Group == 0 {
+			v.byName[field.CLIName] = field
+		}
+	}
+	v.loaded = time.Now()
+	return nil
+}
+
+// check returns the reason each invalid value was rejected, keyed by field
+// name, and whether any of the names is not a known field.
+func (v *fieldValidator) check(values map[string]any) (invalid map[string]string, unknown bool) {
+	invalid = make(map[string]string)
+	for name, value := range values {
+		field, ok := v.byName[name]
+		if !ok {
+			invalid[name] = "unknown field"
+			unknown = true
+			continue
+		}
+		if reason := checkFieldValue(field, value); reason != "" {
+			invalid[name] = reason
+		}
+	}
+	return invalid, unknown
+}
+
+// checkFieldValue returns why value is not valid for field, or "" if it is.
+// A nil value clears the field and is always valid.
+func checkFieldValue(field *IncidentField, value any) string {
+	if value == nil {
+		return ""
+	}
+
+	switch field.Type {
+	case FieldTypeShortText, FieldTypeLongText, FieldTypeMarkdown, FieldTypeHTML,
+		FieldTypeURL, FieldTypeUser, FieldTypeRole:
+		if _, ok := value.(string); !ok {
+			return fmt.Sprintf("expected string, got %T", value)
+		}
+	case FieldTypeNumber:
+		if !isNumber(value) {
+			return fmt.Sprintf("expected number, got %T", value)
+		}
+	case FieldTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("expected bool, got %T", value)
+		}
+	case FieldTypeDate:
+		if !isDate(value) {
+			return fmt.Sprintf("expected time.Time or RFC 3339 string, got %T", value)
+		}
+	case FieldTypeSingleSelect:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("expected string, got %T", value)
+		}
+		return checkSelectValue(field, s)
+	case FieldTypeMultiSelect, FieldTypeTagsSelect:
+		values, ok := stringSlice(value)
+		if !ok {
+			return fmt.Sprintf("expected []string, got %T", value)
+		}
+		// Tags select fields accept values outside the predefined list.
+		if field.Type == FieldTypeTagsSelect {
+			return ""
+		}
+		for _, s := range values {
+			if reason := checkSelectValue(field, s); reason != "" {
+				return reason
+			}
+		}
+	}
+
+	// Grid, timer and attachment fields have structured values that are
+	// left to the server to validate.
+	return ""
+}
+
+// checkSelectValue returns why s is not one of the field's select values.
+func checkSelectValue(field *IncidentField, s string) string {
+	if s == "" || len(field.SelectValues) == 0 || slices.Contains(field.SelectValues, s) {
+		return ""
+	}
+	return fmt.Sprintf("invalid value %q, expected one of %v", s, field.SelectValues)
+}
+
+// isNumber reports whether value is a Go numeric type or a json.Number.
+func isNumber(value any) bool {
+	if _, ok := value.(json.Number); ok {
+		return true
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isDate reports whether value is a time or a string in RFC 3339 format.
+func isDate(value any) bool {
+	switch v := value.(type) {
+	case time.Time, *time.Time:
+		return true
+	case string:
+		_, err := time.Parse(time.RFC3339, v)
+		return err == nil
+	default:
+		return false
+	}
+}
+
+// stringSlice returns value as strings if it is a []string, or a []any
+// holding only strings.
+func stringSlice(value any) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
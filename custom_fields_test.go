@@ -0,0 +1,153 @@
+package xsoar_test
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+const customFieldsResponse = `[
+	{"id": "incident_verdict", "name": "Verdict", "cliName": "verdict", "type": "singleSelect",
+	 "selectValues": ["", "Malicious", "Benign"], "group": 0},
+	{"id": "incident_affectedhosts", "name": "Affected Hosts", "cliName": "affectedhosts", "type": "multiSelect",
+	 "selectValues": ["web01", "web02"], "group": 0},
+	{"id": "incident_riskscore", "name": "Risk Score", "cliName": "riskscore", "type": "number", "group": 0},
+	{"id": "incident_vip", "name": "VIP", "cliName": "vip", "type": "boolean", "group": 0},
+	{"id": "incident_detectedat", "name": "Detected At", "cliName": "detectedat", "type": "date", "group": 0},
+	{"id": "incident_analyst", "name": "Analyst Notes", "cliName": "analystnotes", "type": "longText", "group": 0},
+	{"id": "indicator_tags", "name": "Indicator Tags", "cliName": "indicatortags", "type": "tagsSelect", "group": 2}
+]`
+
+func TestCustomFieldValidation(t *testing.T) {
+	var loads, creates atomic.Int32
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/incidentfields":
+			loads.Add(1)
+			_, err := w.Write([]byte(customFieldsResponse))
+			assert.NoError(t, err)
+		case "/incident", "/incident/update":
+			creates.Add(1)
+			_, err := w.Write([]byte(`{"id": "1"}`))
+			assert.NoError(t, err)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}, xsoar.WithCustomFieldValidation(time.Hour))
+
+	ctx := context.Background()
+
+	t.Run("valid fields", func(t *testing.T) {
+		_, err := client.Incidents.Create(ctx, &xsoar.CreateIncidentRequest{
+			Name: "Phishing report",
+			Type: "Phishing",
+			CustomFields: map[string]any{
+				"verdict":       "Malicious",
+				"affectedhosts": []string{"web01"},
+				"riskscore":     87.5,
+				"vip":           true,
+				"detectedat":    time.Now(),
+				"analystnotes":  "reported by user",
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), creates.Load())
+	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		err := client.Incidents.Update(ctx, "1", &xsoar.UpdateIncidentRequest{
+			CustomFields: map[string]any{
+				"verdict":       "Suspicious",
+				"affectedhosts": []string{"web01", "db01"},
+				"riskscore":     "high",
+				"vip":           "yes",
+				"detectedat":    "yesterday",
+				"indicatortags": []string{"apt"},
+				"analystnotes":  nil,
+			},
+		})
+
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.ElementsMatch(t,
+			[]string{"verdict", "affectedhosts", "riskscore", "vip", "detectedat", "indicatortags"},
+			slices.Collect(maps.Keys(validationErr.Fields)))
+		assert.Equal(t, "unknown field", validationErr.Fields["indicatortags"])
+		assert.Contains(t, validationErr.Fields["verdict"], `"Suspicious"`)
+		assert.Equal(t, int32(1), creates.Load(), "invalid request should not be sent")
+	})
+
+	t.Run("definitions are cached", func(t *testing.T) {
+		err := client.Incidents.Update(ctx, "1", &xsoar.UpdateIncidentRequest{
+			CustomFields: map[string]any{"riskscore": 10},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), loads.Load())
+	})
+}
+
+func TestCustomFieldValidation_UpdateWithRetry(t *testing.T) {
+	var updates atomic.Int32
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/incidentfields":
+			_, err := w.Write([]byte(customFieldsResponse))
+			assert.NoError(t, err)
+		case "/incident/1":
+			// legacyfield is no longer defined but is left untouched by
+			// mutate, so it must not fail validation.
+			_, err := w.Write([]byte(`{"id": "1", "version": 3,
+				"CustomFields": {"verdict": "Benign", "legacyfield": "x"}}`))
+			assert.NoError(t, err)
+		case "/incident/update":
+			updates.Add(1)
+			_, err := w.Write([]byte(`{"id": "1", "version": 4}`))
+			assert.NoError(t, err)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}, xsoar.WithCustomFieldValidation(time.Hour))
+
+	ctx := context.Background()
+
+	_, err := client.Incidents.UpdateWithRetry(ctx, "1", func(incident *xsoar.Incident) error {
+		incident.CustomFields["verdcit"] = "Malicious"
+		return nil
+	})
+	var validationErr *xsoar.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, map[string]string{"verdcit": "unknown field"}, validationErr.Fields)
+	assert.Equal(t, int32(0), updates.Load(), "invalid update should not be sent")
+
+	incident, err := client.Incidents.UpdateWithRetry(ctx, "1", func(incident *xsoar.Incident) error {
+		incident.CustomFields["verdict"] = "Malicious"
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, incident.Version)
+	assert.Equal(t, int32(1), updates.Load())
+}
+
+func TestCustomFieldValidation_Disabled(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/incident", r.URL.Path, "field definitions should not be loaded")
+		_, err := w.Write([]byte(`{"id": "1"}`))
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+	_, err := client.Incidents.Create(ctx, &xsoar.CreateIncidentRequest{
+		Name:         "Phishing report",
+		Type:         "Phishing",
+		CustomFields: map[string]any{"nosuchfield": 1},
+	})
+	require.NoError(t, err)
+}
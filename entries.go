@@ -0,0 +1,236 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// EntryService provides operations on War Room entries.
+//
+// Entries belong to an investigation; use Incident.InvestigateID to
+// address the War Room of an incident.
+//
+//go:generate mockery --name=EntryService --output=mocks --outpkg=mocks --filename=entry_service.go
+type EntryService interface {
+	// List returns an iterator over the entries of an investigation.
+	// The iterator fetches pages lazily as you iterate.
+	List(ctx context.Context, investigationID string, filter *EntryFilter, opts ...RequestOption) iter.Seq2[*Entry, error]
+
+	// AddNote adds a note to an investigation's War Room.
+	// Use EntryFormatText, EntryFormatMarkdown, or EntryFormatHTML as the format.
+	// If the entry is created but cannot be marked as a note, the entry is
+	// returned together with the error, so that the call is not repeated.
+	AddNote(ctx context.Context, investigationID, contents string, format EntryFormat, opts ...RequestOption) (*Entry, error)
+
+	// AddFormatted adds a formatted entry to an investigation's War Room.
+	AddFormatted(ctx context.Context, req *AddEntryRequest, opts ...RequestOption) (*Entry, error)
+
+	// MarkNote marks or unmarks an entry as a note.
+	MarkNote(ctx context.Context, investigationID, entryID string, note bool, opts ...RequestOption) error
+
+	// MarkEvidence marks an entry as evidence.
+	MarkEvidence(ctx context.Context, req *EvidenceRequest, opts ...RequestOption) error
+
+	// Tag sets the tags of an entry.
+	Tag(ctx context.Context, investigationID, entryID string, tags []string, opts ...RequestOption) error
+}
+
+// entryService implements EntryService.
+type entryService struct {
+	transport *api.Transport
+}
+
+func newEntryService(transport *api.Transport) *entryService {
+	return &entryService{transport: transport}
+}
+
+// List returns an iterator over the entries of an investigation.
+func (s *entryService) List(ctx context.Context, investigationID string, filter *EntryFilter, opts ...RequestOption) iter.Seq2[*Entry, error] {
+	return func(yield func(*Entry, error) bool) {
+		if err := validateRequired("investigation ID", investigationID); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		reqCfg := newRequestConfig()
+		reqCfg.apply(opts...)
+
+		body := &entryListRequest{
+			EntryFilter: filter,
+			PageSize:    reqCfg.pageLimit(),
+		}
+		seen := 0
+
+		for {
+			var result entryListResponse
+			err := doRequest(ctx, s.transport, &api.Request{
+				Method:     http.MethodPost,
+				Path:       fmt.Sprintf("/investigation/%s", url.PathEscape(investigationID)),
+				Body:       body,
+				Headers:    reqCfg.headers,
+				Idempotent: true,
+			}, &result)
+			if err != nil {
+				yield(nil, withResource(err, "investigation", investigationID))
+				return
+			}
+
+			if !yieldItems(ctx, result.Entries, yield) {
+				return
+			}
+
+			// Total is omitted by some server versions; only a short
+			// page ends the listing then.
+			seen += len(result.Entries)
+			if len(result.Entries) < body.PageSize || (result.Total > 0 && seen >= result.Total) {
+				return
+			}
+			body.LastID = result.Entries[len(result.Entries)-1].ID
+		}
+	}
+}
+
+// AddNote adds a note to an investigation's War Room.
+func (s *entryService) AddNote(ctx context.Context, investigationID, contents string, format EntryFormat, opts ...RequestOption) (*Entry, error) {
+	entry, err := s.AddFormatted(ctx, &AddEntryRequest{
+		InvestigationID: investigationID,
+		Format:          format,
+		Contents:        contents,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.MarkNote(ctx, investigationID, entry.ID, true, opts...); err != nil {
+		return entry, err
+	}
+	entry.Note = true
+
+	return entry, nil
+}
+
+// AddFormatted adds a formatted entry to an investigation's War Room.
+func (s *entryService) AddFormatted(ctx context.Context, req *AddEntryRequest, opts ...RequestOption) (*Entry, error) {
+	if req == nil {
+		return nil, &ValidationError{
+			APIError: APIError{Message: "entry request cannot be nil"},
+		}
+	}
+	if err := validateRequired("investigation ID", req.InvestigationID); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	body := *req
+	if body.Format == "" {
+		body.Format = EntryFormatText
+	}
+
+	var result Entry
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/entry/formatted",
+		Body:       &body,
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, &result)
+	if err != nil {
+		return nil, withResource(err, "investigation", req.InvestigationID)
+	}
+
+	return &result, nil
+}
+
+// MarkNote marks or unmarks an entry as a note.
+func (s *entryService) MarkNote(ctx context.Context, investigationID, entryID string, note bool, opts ...RequestOption) error {
+	if err := validateEntryRef(investigationID, entryID); err != nil {
+		return err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	// Marking is a state toggle to a fixed value, so it is always safe to retry.
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method: http.MethodPost,
+		Path:   "/entry/note",
+		Body: map[string]any{
+			"id":              entryID,
+			"investigationId": investigationID,
+			"data":            strconv.FormatBool(note),
+		},
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, nil)
+
+	return withResource(err, "entry", entryID)
+}
+
+// MarkEvidence marks an entry as evidence.
+func (s *entryService) MarkEvidence(ctx context.Context, req *EvidenceRequest, opts ...RequestOption) error {
+	if req == nil {
+		return &ValidationError{
+			APIError: APIError{Message: "evidence request cannot be nil"},
+		}
+	}
+	if err := validateEntryRef(req.InvestigationID, req.EntryID); err != nil {
+		return err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/evidence",
+		Body:       req,
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, nil)
+
+	return withResource(err, "entry", req.EntryID)
+}
+
+// Tag sets the tags of an entry.
+func (s *entryService) Tag(ctx context.Context, investigationID, entryID string, tags []string, opts ...RequestOption) error {
+	if err := validateEntryRef(investigationID, entryID); err != nil {
+		return err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	if tags == nil {
+		tags = []string{}
+	}
+
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method: http.MethodPost,
+		Path:   "/entry/tags",
+		Body: map[string]any{
+			"id":              entryID,
+			"investigationId": investigationID,
+			"tags":            tags,
+		},
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, nil)
+
+	return withResource(err, "entry", entryID)
+}
+
+// validateEntryRef checks that both parts of an entry reference are set.
+func validateEntryRef(investigationID, entryID string) error {
+	if err := validateRequired("investigation ID", investigationID); err != nil {
+		return err
+	}
+	return validateRequired("entry ID", entryID)
+}
@@ -0,0 +1,235 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+func TestEntryService_List(t *testing.T) {
+	t.Run("iterates all pages", func(t *testing.T) {
+		var lastIDs []any
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/investigation/inv-1", r.URL.Path)
+
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, []any{"notes"}, reqBody["categories"])
+			lastIDs = append(lastIDs, reqBody["lastId"])
+
+			size, ok := reqBody["pageSize"].(float64)
+			assert.True(t, ok, "pageSize should be a number")
+			pageSize := int(size)
+			entries := make([]*xsoar.Entry, 0, pageSize)
+			count := pageSize
+			if reqBody["lastId"] != nil {
+				count = 1
+			}
+			for i := range count {
+				entries = append(entries, &xsoar.Entry{ID: fmt.Sprintf("%d@inv-1", len(lastIDs)*1000+i)})
+			}
+
+			err = json.NewEncoder(w).Encode(map[string]any{"entries": entries, "total": pageSize + 1})
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		entries, err := xsoar.Collect(client.Entries.List(ctx, "inv-1", &xsoar.EntryFilter{
+			Categories: []string{"notes"},
+		}))
+		require.NoError(t, err)
+
+		assert.Len(t, entries, 101)
+		require.Len(t, lastIDs, 2)
+		assert.Nil(t, lastIDs[0])
+		assert.Equal(t, "1099@inv-1", lastIDs[1])
+	})
+
+	t.Run("pages without a total", func(t *testing.T) {
+		var requests int
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			count := 2
+			if requests == 3 {
+				count = 1
+			}
+			entries := make([]*xsoar.Entry, 0, count)
+			for i := range count {
+				entries = append(entries, &xsoar.Entry{ID: fmt.Sprintf("%d@inv-1", requests*10+i)})
+			}
+
+			err := json.NewEncoder(w).Encode(map[string]any{"entries": entries})
+			assert.NoError(t, err)
+		})
+
+		entries, err := xsoar.Collect(client.Entries.List(context.Background(), "inv-1", nil, xsoar.WithPageSize(2)))
+		require.NoError(t, err)
+
+		assert.Len(t, entries, 5)
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		_, err := xsoar.Collect(client.Entries.List(context.Background(), "missing", nil))
+		require.Error(t, err)
+
+		var notFoundErr *xsoar.NotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "investigation", notFoundErr.ResourceType)
+		assert.Equal(t, "missing", notFoundErr.ResourceID)
+	})
+
+	t.Run("empty investigation ID returns validation error", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call with empty ID")
+		})
+
+		_, err := xsoar.Collect(client.Entries.List(context.Background(), "", nil))
+
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestEntryService_AddNote(t *testing.T) {
+	t.Run("adds and marks the note", func(t *testing.T) {
+		var paths []string
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, "inv-1", reqBody["investigationId"])
+
+			switch r.URL.Path {
+			case "/entry/formatted":
+				assert.Equal(t, "markdown", reqBody["format"])
+				assert.Equal(t, "**triaged**", reqBody["contents"])
+				err = json.NewEncoder(w).Encode(xsoar.Entry{ID: "5@inv-1", InvestigationID: "inv-1", Contents: "**triaged**"})
+				assert.NoError(t, err)
+			case "/entry/note":
+				assert.Equal(t, "5@inv-1", reqBody["id"])
+				assert.Equal(t, "true", reqBody["data"])
+			}
+		})
+
+		entry, err := client.Entries.AddNote(context.Background(), "inv-1", "**triaged**", xsoar.EntryFormatMarkdown)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"/entry/formatted", "/entry/note"}, paths)
+		assert.Equal(t, "5@inv-1", entry.ID)
+		assert.True(t, entry.Note)
+		assert.Equal(t, "**triaged**", entry.ContentsString())
+	})
+
+	t.Run("returns the entry when marking fails", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/entry/note" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			err := json.NewEncoder(w).Encode(xsoar.Entry{ID: "5@inv-1", InvestigationID: "inv-1"})
+			assert.NoError(t, err)
+		})
+
+		entry, err := client.Entries.AddNote(context.Background(), "inv-1", "triaged", xsoar.EntryFormatText)
+		require.Error(t, err)
+
+		require.NotNil(t, entry, "the created entry is returned with the error")
+		assert.Equal(t, "5@inv-1", entry.ID)
+		assert.False(t, entry.Note)
+	})
+}
+
+func TestEntryService_AddFormatted(t *testing.T) {
+	t.Run("defaults to text format", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var reqBody xsoar.AddEntryRequest
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, xsoar.EntryFormatText, reqBody.Format)
+
+			err = json.NewEncoder(w).Encode(xsoar.Entry{ID: "6@inv-1"})
+			assert.NoError(t, err)
+		})
+
+		entry, err := client.Entries.AddFormatted(context.Background(), &xsoar.AddEntryRequest{
+			InvestigationID: "inv-1",
+			Contents:        "plain text",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "6@inv-1", entry.ID)
+	})
+
+	t.Run("nil request returns validation error", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call with nil request")
+		})
+
+		_, err := client.Entries.AddFormatted(context.Background(), nil)
+
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestEntryService_MarkEvidence(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/evidence", r.URL.Path)
+
+		var reqBody map[string]any
+		err := json.NewDecoder(r.Body).Decode(&reqBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "5@inv-1", reqBody["entryId"])
+		assert.Equal(t, "inv-1", reqBody["investigationId"])
+		assert.Equal(t, "phishing email", reqBody["description"])
+	})
+
+	err := client.Entries.MarkEvidence(context.Background(), &xsoar.EvidenceRequest{
+		InvestigationID: "inv-1",
+		EntryID:         "5@inv-1",
+		Description:     "phishing email",
+	})
+	require.NoError(t, err)
+}
+
+func TestEntryService_Tag(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/entry/tags", r.URL.Path)
+
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, "5@inv-1", reqBody["id"])
+			assert.Equal(t, []any{"ioc", "reviewed"}, reqBody["tags"])
+		})
+
+		err := client.Entries.Tag(context.Background(), "inv-1", "5@inv-1", []string{"ioc", "reviewed"})
+		require.NoError(t, err)
+	})
+
+	t.Run("empty entry ID returns validation error", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call with empty ID")
+		})
+
+		err := client.Entries.Tag(context.Background(), "inv-1", "", []string{"ioc"})
+
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
@@ -5,8 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
 	"time"
+
+	"github.com/tphakala/go-xsoar/internal/api"
 )
 
 // Sentinel errors for common failure modes.
@@ -133,6 +134,37 @@ func (e *ServerError) As(target any) bool {
 	return false
 }
 
+// ConflictError indicates that a write was rejected because the resource
+// was modified concurrently (409). Re-read the resource and retry.
+type ConflictError struct {
+	APIError
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("xsoar: conflict: %s", e.Message)
+}
+
+// As implements error unwrapping for errors.As to match *APIError.
+func (e *ConflictError) As(target any) bool {
+	if t, ok := target.(**APIError); ok {
+		*t = &e.APIError
+		return true
+	}
+	return false
+}
+
+// CommandError indicates that a command executed in an investigation
+// produced an error entry.
+type CommandError struct {
+	Command string
+	EntryID string
+	Message string
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("xsoar: command %s failed: %s", e.Command, e.Message)
+}
+
 // parseError converts an HTTP response into the appropriate error type.
 func parseError(statusCode int, body []byte, headers http.Header) error {
 	requestID := headers.Get("X-Request-ID")
@@ -162,10 +194,12 @@ func parseError(statusCode int, body []byte, headers http.Header) error {
 			validationErr.Fields = fieldData.Fields
 		}
 		return validationErr
+	case statusCode == http.StatusConflict:
+		return &ConflictError{APIError: base}
 	case statusCode == http.StatusTooManyRequests:
 		return &RateLimitError{
 			APIError:   base,
-			RetryAfter: parseRetryAfter(headers.Get("Retry-After")),
+			RetryAfter: api.ParseRetryAfter(headers.Get("Retry-After")),
 		}
 	case statusCode >= http.StatusInternalServerError:
 		return &ServerError{APIError: base}
@@ -173,26 +207,3 @@ func parseError(statusCode int, body []byte, headers http.Header) error {
 		return &base
 	}
 }
-
-// parseRetryAfter parses the Retry-After header value.
-// It handles both seconds (integer) and HTTP-date formats.
-func parseRetryAfter(value string) time.Duration {
-	if value == "" {
-		return 0
-	}
-
-	// Try parsing as seconds first
-	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
-		return time.Duration(seconds) * time.Second
-	}
-
-	// Try parsing as HTTP-date (RFC 1123)
-	if t, err := time.Parse(time.RFC1123, value); err == nil {
-		duration := time.Until(t)
-		if duration > 0 {
-			return duration
-		}
-	}
-
-	return 0
-}
@@ -117,6 +117,25 @@ func TestServerError(t *testing.T) {
 	assert.Equal(t, "xsoar: server error 503: service unavailable", err.Error())
 }
 
+func TestConflictError(t *testing.T) {
+	err := &xsoar.ConflictError{
+		APIError: xsoar.APIError{
+			StatusCode: 409,
+			Message:    "incident version mismatch",
+		},
+	}
+	assert.Equal(t, "xsoar: conflict: incident version mismatch", err.Error())
+}
+
+func TestCommandError(t *testing.T) {
+	err := &xsoar.CommandError{
+		Command: "ip",
+		EntryID: "2@inv-1",
+		Message: "Unsupported command",
+	}
+	assert.Equal(t, "xsoar: command ip failed: Unsupported command", err.Error())
+}
+
 func TestErrorsAs(t *testing.T) {
 	// Test that all error types can be detected with errors.As
 	tests := []struct {
@@ -126,6 +145,7 @@ func TestErrorsAs(t *testing.T) {
 		{"AuthenticationError", &xsoar.AuthenticationError{APIError: xsoar.APIError{StatusCode: 401}}},
 		{"NotFoundError", &xsoar.NotFoundError{APIError: xsoar.APIError{StatusCode: 404}}},
 		{"ValidationError", &xsoar.ValidationError{APIError: xsoar.APIError{StatusCode: 400}}},
+		{"ConflictError", &xsoar.ConflictError{APIError: xsoar.APIError{StatusCode: 409}}},
 		{"RateLimitError", &xsoar.RateLimitError{APIError: xsoar.APIError{StatusCode: 429}}},
 		{"ServerError", &xsoar.ServerError{APIError: xsoar.APIError{StatusCode: 500}}},
 	}
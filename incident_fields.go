@@ -0,0 +1,132 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// IncidentFieldService provides operations on incident field definitions.
+//
+//go:generate mockery --name=IncidentFieldService --output=mocks --outpkg=mocks --filename=incident_field_service.go
+type IncidentFieldService interface {
+	// List returns all incident field definitions, both system and custom.
+	List(ctx context.Context, opts ...RequestOption) ([]*IncidentField, error)
+
+	// Get retrieves a field by CLI name or ID.
+	Get(ctx context.Context, name string, opts ...RequestOption) (*IncidentField, error)
+
+	// Save creates a custom field or updates an existing one with the
+	// same ID. The field type of an existing field cannot be changed.
+	Save(ctx context.Context, field *IncidentField, opts ...RequestOption) (*IncidentField, error)
+
+	// Delete removes a custom field by ID.
+	Delete(ctx context.Context, id string, opts ...RequestOption) error
+}
+
+// incidentFieldService implements IncidentFieldService.
+type incidentFieldService struct {
+	transport *api.Transport
+}
+
+func newIncidentFieldService(transport *api.Transport) *incidentFieldService {
+	return &incidentFieldService{transport: transport}
+}
+
+// List returns all incident field definitions.
+func (s *incidentFieldService) List(ctx context.Context, opts ...RequestOption) ([]*IncidentField, error) {
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result []*IncidentField
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodGet,
+		Path:       "/incidentfields",
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Get retrieves a field by CLI name or ID.
+func (s *incidentFieldService) Get(ctx context.Context, name string, opts ...RequestOption) (*IncidentField, error) {
+	if err := validateRequired("field name", name); err != nil {
+		return nil, err
+	}
+
+	fields, err := s.List(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(fields, func(field *IncidentField) bool {
+		return field.CLIName == name || field.ID == name
+	})
+	if index < 0 {
+		return nil, &NotFoundError{
+			APIError:     APIError{StatusCode: http.StatusNotFound, Message: "incident field not found"},
+			ResourceType: "incident field",
+			ResourceID:   name,
+		}
+	}
+
+	return fields[index], nil
+}
+
+// Save creates or updates a custom field.
+func (s *incidentFieldService) Save(ctx context.Context, field *IncidentField, opts ...RequestOption) (*IncidentField, error) {
+	if field == nil {
+		return nil, &ValidationError{APIError: APIError{Message: "field cannot be nil"}}
+	}
+	if err := validateRequired("field name", field.Name); err != nil {
+		return nil, err
+	}
+	if err := validateRequired("field CLI name", field.CLIName); err != nil {
+		return nil, err
+	}
+	if err := validateRequired("field type", string(field.Type)); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result IncidentField
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/incidentfield",
+		Body:       field,
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Delete removes a custom field by ID.
+func (s *incidentFieldService) Delete(ctx context.Context, id string, opts ...RequestOption) error {
+	if err := validateRequired("field ID", id); err != nil {
+		return err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:  http.MethodDelete,
+		Path:    fmt.Sprintf("/incidentfield/%s", url.PathEscape(id)),
+		Headers: reqCfg.headers,
+	}, nil)
+	return withResource(err, "incident field", id)
+}
@@ -0,0 +1,109 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+const incidentFieldsResponse = `[
+	{"id": "incident_severity", "name": "Severity", "cliName": "severity", "type": "number", "system": true, "associatedToAll": true},
+	{"id": "incident_phishingverdict", "name": "Phishing Verdict", "cliName": "phishingverdict", "type": "singleSelect",
+	 "selectValues": ["", "Malicious", "Benign"], "required": true, "associatedTypes": ["Phishing"]}
+]`
+
+func TestIncidentFieldService_List(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/incidentfields", r.URL.Path)
+		_, err := w.Write([]byte(incidentFieldsResponse))
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+	fields, err := client.IncidentFields.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, fields, 2)
+
+	verdict := fields[1]
+	assert.Equal(t, xsoar.FieldTypeSingleSelect, verdict.Type)
+	assert.Equal(t, []string{"Malicious", "Benign"}, verdict.SelectValues)
+	assert.True(t, verdict.Required)
+	assert.True(t, verdict.AppliesTo("Phishing"))
+	assert.False(t, verdict.AppliesTo("Malware"))
+	assert.True(t, fields[0].AppliesTo("Malware"))
+}
+
+func TestIncidentFieldService_Get(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(incidentFieldsResponse))
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+
+	field, err := client.IncidentFields.Get(ctx, "phishingverdict")
+	require.NoError(t, err)
+	assert.Equal(t, "incident_phishingverdict", field.ID)
+
+	_, err = client.IncidentFields.Get(ctx, "missing")
+	var notFoundErr *xsoar.NotFoundError
+	require.ErrorAs(t, err, &notFoundErr)
+	assert.Equal(t, "incident field", notFoundErr.ResourceType)
+}
+
+func TestIncidentFieldService_Save(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/incidentfield", r.URL.Path)
+
+			var field xsoar.IncidentField
+			err := json.NewDecoder(r.Body).Decode(&field)
+			assert.NoError(t, err)
+			assert.Equal(t, "ransomfamily", field.CLIName)
+			assert.Equal(t, []string{"Phishing"}, field.AssociatedTypes)
+
+			field.ID = "incident_ransomfamily"
+			err = json.NewEncoder(w).Encode(field)
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		field, err := client.IncidentFields.Save(ctx, &xsoar.IncidentField{
+			Name:            "Ransom Family",
+			CLIName:         "ransomfamily",
+			Type:            xsoar.FieldTypeShortText,
+			AssociatedTypes: []string{"Phishing"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "incident_ransomfamily", field.ID)
+	})
+
+	t.Run("missing type", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call for an incomplete field")
+		})
+
+		ctx := context.Background()
+		_, err := client.IncidentFields.Save(ctx, &xsoar.IncidentField{Name: "Ransom Family", CLIName: "ransomfamily"})
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestIncidentFieldService_Delete(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/incidentfield/incident_ransomfamily", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ctx := context.Background()
+	require.NoError(t, client.IncidentFields.Delete(ctx, "incident_ransomfamily"))
+}
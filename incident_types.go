@@ -0,0 +1,127 @@
+package xsoar
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// IncidentTypeService provides operations on incident types.
+//
+//go:generate mockery --name=IncidentTypeService --output=mocks --outpkg=mocks --filename=incident_type_service.go
+type IncidentTypeService interface {
+	// List returns all incident types.
+	List(ctx context.Context, opts ...RequestOption) ([]*IncidentType, error)
+
+	// Get retrieves an incident type by name or ID.
+	Get(ctx context.Context, name string, opts ...RequestOption) (*IncidentType, error)
+
+	// Save creates an incident type or updates an existing one with the
+	// same ID. If incidentType.Version is set, the server rejects the write
+	// with a ConflictError when the type has been modified since it was read.
+	Save(ctx context.Context, incidentType *IncidentType, opts ...RequestOption) (*IncidentType, error)
+
+	// Delete removes an incident type by ID.
+	Delete(ctx context.Context, id string, opts ...RequestOption) error
+}
+
+// incidentTypeService implements IncidentTypeService.
+type incidentTypeService struct {
+	transport *api.Transport
+}
+
+func newIncidentTypeService(transport *api.Transport) *incidentTypeService {
+	return &incidentTypeService{transport: transport}
+}
+
+// List returns all incident types.
+func (s *incidentTypeService) List(ctx context.Context, opts ...RequestOption) ([]*IncidentType, error) {
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result []*IncidentType
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodGet,
+		Path:       "/incidenttype",
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Get retrieves an incident type by name or ID.
+func (s *incidentTypeService) Get(ctx context.Context, name string, opts ...RequestOption) (*IncidentType, error) {
+	if err := validateRequired("incident type name", name); err != nil {
+		return nil, err
+	}
+
+	types, err := s.List(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(types, func(incidentType *IncidentType) bool {
+		return incidentType.Name == name || incidentType.ID == name
+	})
+	if index < 0 {
+		return nil, &NotFoundError{
+			APIError:     APIError{StatusCode: http.StatusNotFound, Message: "incident type not found"},
+			ResourceType: "incident type",
+			ResourceID:   name,
+		}
+	}
+
+	return types[index], nil
+}
+
+// Save creates or updates an incident type.
+func (s *incidentTypeService) Save(ctx context.Context, incidentType *IncidentType, opts ...RequestOption) (*IncidentType, error) {
+	if incidentType == nil {
+		return nil, &ValidationError{APIError: APIError{Message: "incident type cannot be nil"}}
+	}
+	if err := validateRequired("incident type name", incidentType.Name); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result IncidentType
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/incidenttype",
+		Body:       incidentType,
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Delete removes an incident type by ID.
+func (s *incidentTypeService) Delete(ctx context.Context, id string, opts ...RequestOption) error {
+	if err := validateRequired("incident type ID", id); err != nil {
+		return err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/incidenttype/delete",
+		Body:       map[string]any{"id": id},
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, nil)
+	return withResource(err, "incident type", id)
+}
@@ -0,0 +1,84 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+func TestIncidentTypeService_Get(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/incidenttype", r.URL.Path)
+
+		_, err := w.Write([]byte(`[
+			{"id": "Phishing", "name": "Phishing", "playbookId": "Phishing - Generic v3", "layout": "Phishing Layout", "autorun": true},
+			{"id": "Malware", "name": "Malware", "disabled": true}
+		]`))
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+
+	t.Run("found", func(t *testing.T) {
+		incidentType, err := client.IncidentTypes.Get(ctx, "Phishing")
+		require.NoError(t, err)
+		assert.Equal(t, "Phishing - Generic v3", incidentType.Playbook)
+		assert.Equal(t, "Phishing Layout", incidentType.Layout)
+		assert.True(t, incidentType.AutoRun)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := client.IncidentTypes.Get(ctx, "Ransomware")
+		var notFoundErr *xsoar.NotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "incident type", notFoundErr.ResourceType)
+	})
+}
+
+func TestIncidentTypeService_Save(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/incidenttype", r.URL.Path)
+
+		var incidentType xsoar.IncidentType
+		err := json.NewDecoder(r.Body).Decode(&incidentType)
+		assert.NoError(t, err)
+		assert.Equal(t, "Ransomware", incidentType.Name)
+
+		incidentType.ID = "Ransomware"
+		incidentType.Version = 1
+		err = json.NewEncoder(w).Encode(incidentType)
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+	incidentType, err := client.IncidentTypes.Save(ctx, &xsoar.IncidentType{Name: "Ransomware", Color: "#ff0000"})
+	require.NoError(t, err)
+	assert.Equal(t, "Ransomware", incidentType.ID)
+	assert.Equal(t, 1, incidentType.Version)
+
+	_, err = client.IncidentTypes.Save(ctx, &xsoar.IncidentType{})
+	var validationErr *xsoar.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func TestIncidentTypeService_Delete(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/incidenttype/delete", r.URL.Path)
+
+		var reqBody map[string]any
+		err := json.NewDecoder(r.Body).Decode(&reqBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "Ransomware", reqBody["id"])
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ctx := context.Background()
+	require.NoError(t, client.IncidentTypes.Delete(ctx, "Ransomware"))
+}
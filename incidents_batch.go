@@ -0,0 +1,160 @@
+package xsoar
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// incidentBatchSize is the number of IDs sent per batch request.
+const incidentBatchSize = 500
+
+// BatchDelete deletes the selected incidents.
+func (s *incidentService) BatchDelete(ctx context.Context, selection IncidentSelection, opts ...RequestOption) (*BatchReport, error) {
+	return s.batch(ctx, "/incident/batchDelete", selection, &batchRequest{}, opts)
+}
+
+// BatchClose closes the selected incidents.
+func (s *incidentService) BatchClose(ctx context.Context, selection IncidentSelection, req *CloseIncidentRequest, opts ...RequestOption) (*BatchReport, error) {
+	if req == nil {
+		req = &CloseIncidentRequest{}
+	}
+	return s.batch(ctx, "/incident/batchClose", selection, &batchRequest{
+		CloseReason: req.Reason,
+		CloseNotes:  req.Notes,
+		CloseDate:   req.CloseDate,
+	}, opts)
+}
+
+// BatchUpdate applies an update to the selected incidents.
+func (s *incidentService) BatchUpdate(ctx context.Context, selection IncidentSelection, req *UpdateIncidentRequest, opts ...RequestOption) (*BatchReport, error) {
+	if req == nil {
+		return nil, &ValidationError{APIError: APIError{Message: "update request cannot be nil"}}
+	}
+	if err := s.validateCustomFields(ctx, req.CustomFields, opts); err != nil {
+		return nil, err
+	}
+	return s.batch(ctx, "/incident/batchUpdate", selection, &batchRequest{
+		Data:         updateFields(req),
+		CustomFields: req.CustomFields,
+	}, opts)
+}
+
+// batch sends a batch operation for the selection. Filter selections are
+// sent as a single request; ID selections are chunked. On error, the report
+// covers the chunks completed so far.
+func (s *incidentService) batch(ctx context.Context, path string, selection IncidentSelection, template *batchRequest, opts []RequestOption) (*BatchReport, error) {
+	if err := validateSelection(selection); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	send := func(body *batchRequest) (*batchResponse, error) {
+		var result batchResponse
+		err := doRequest(ctx, s.transport, &api.Request{
+			Method:     http.MethodPost,
+			Path:       path,
+			Body:       body,
+			Headers:    reqCfg.headers,
+			Idempotent: reqCfg.idempotent,
+		}, &result)
+		if err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+
+	report := &BatchReport{}
+
+	if selection.Filter != nil {
+		body := *template
+		body.Filter = selection.Filter
+		body.All = true
+
+		result, err := send(&body)
+		if err != nil {
+			return report, err
+		}
+		for _, incident := range result.Data {
+			report.add(BatchItemResult{ID: incident.ID, Status: BatchItemSucceeded, Incident: incident})
+		}
+		report.Failed += result.NotUpdated
+		return report, nil
+	}
+
+	ids := make([]string, 0, len(selection.IDs))
+	for _, id := range selection.IDs {
+		if id == "" {
+			report.add(BatchItemResult{ID: id, Status: BatchItemFailed, Reason: "incident ID cannot be empty"})
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	for chunk := range slices.Chunk(ids, incidentBatchSize) {
+		body := *template
+		body.IDs = chunk
+
+		result, err := send(&body)
+		if err != nil {
+			return report.sorted(selection.IDs), err
+		}
+
+		processed := make(map[string]*Incident, len(result.Data))
+		for _, incident := range result.Data {
+			processed[incident.ID] = incident
+		}
+		for _, id := range chunk {
+			incident, ok := processed[id]
+			if !ok {
+				report.add(BatchItemResult{ID: id, Status: BatchItemFailed, Reason: "not processed by server"})
+				continue
+			}
+			report.add(BatchItemResult{ID: id, Status: BatchItemSucceeded, Incident: incident})
+		}
+	}
+
+	return report.sorted(selection.IDs), nil
+}
+
+// validateSelection checks that exactly one of IDs or a non-empty filter is
+// set. An empty filter would select every incident and is rejected.
+func validateSelection(selection IncidentSelection) error {
+	switch {
+	case selection.Filter != nil && len(selection.IDs) > 0:
+		return &ValidationError{APIError: APIError{Message: "selection cannot have both IDs and a filter"}}
+	case selection.Filter != nil && reflect.ValueOf(*selection.Filter).IsZero():
+		return &ValidationError{APIError: APIError{Message: "selection filter cannot be empty"}}
+	case selection.Filter == nil && len(selection.IDs) == 0:
+		return &ValidationError{APIError: APIError{Message: "selection cannot be empty"}}
+	}
+	return nil
+}
+
+// add records a result and updates the tallies.
+func (r *BatchReport) add(result BatchItemResult) {
+	r.Results = append(r.Results, result)
+	switch result.Status {
+	case BatchItemSucceeded:
+		r.Succeeded++
+	case BatchItemFailed:
+		r.Failed++
+	}
+}
+
+// sorted orders results by the position of their ID in the request.
+func (r *BatchReport) sorted(ids []string) *BatchReport {
+	position := make(map[string]int, len(ids))
+	for i, id := range slices.Backward(ids) {
+		position[id] = i
+	}
+	slices.SortStableFunc(r.Results, func(a, b BatchItemResult) int {
+		return position[a.ID] - position[b.ID]
+	})
+	return r
+}
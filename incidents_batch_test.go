@@ -0,0 +1,220 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+func TestIncidentService_BatchDelete(t *testing.T) {
+	t.Run("chunks large ID lists and reports per-ID outcomes", func(t *testing.T) {
+		ids := make([]string, 1200)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("inc-%04d", i)
+		}
+
+		var chunks []int
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/incident/batchDelete", r.URL.Path)
+
+			var reqBody struct {
+				IDs []string `json:"ids"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			chunks = append(chunks, len(reqBody.IDs))
+
+			var data []*xsoar.Incident
+			for _, id := range reqBody.IDs {
+				if id != "inc-0007" {
+					data = append(data, &xsoar.Incident{ID: id})
+				}
+			}
+			err = json.NewEncoder(w).Encode(map[string]any{"data": data, "total": len(data), "notUpdated": 1})
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		report, err := client.Incidents.BatchDelete(ctx, xsoar.IncidentIDs(ids...))
+		require.NoError(t, err)
+
+		assert.Equal(t, []int{500, 500, 200}, chunks)
+		require.Len(t, report.Results, 1200)
+		assert.Equal(t, 1199, report.Succeeded)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, "inc-0007", report.Results[7].ID)
+		assert.Equal(t, xsoar.BatchItemFailed, report.Results[7].Status)
+		assert.Equal(t, xsoar.BatchItemSucceeded, report.Results[8].Status)
+	})
+
+	t.Run("rejects empty IDs without sending them", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var reqBody struct {
+				IDs []string `json:"ids"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"inc-1", "inc-2"}, reqBody.IDs)
+
+			err = json.NewEncoder(w).Encode(map[string]any{
+				"data": []*xsoar.Incident{{ID: "inc-1"}, {ID: "inc-2"}},
+			})
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		report, err := client.Incidents.BatchDelete(ctx, xsoar.IncidentIDs("inc-1", "", "inc-2"))
+		require.NoError(t, err)
+
+		require.Len(t, report.Results, 3)
+		assert.Equal(t, "inc-1", report.Results[0].ID)
+		assert.Equal(t, xsoar.BatchItemFailed, report.Results[1].Status)
+		assert.Equal(t, "incident ID cannot be empty", report.Results[1].Reason)
+		assert.Equal(t, "inc-2", report.Results[2].ID)
+	})
+
+	t.Run("returns partial report on error", func(t *testing.T) {
+		ids := make([]string, 600)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("inc-%d", i)
+		}
+
+		callCount := 0
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			callCount++
+			if callCount == 2 {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			var reqBody struct {
+				IDs []string `json:"ids"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+
+			data := make([]*xsoar.Incident, 0, len(reqBody.IDs))
+			for _, id := range reqBody.IDs {
+				data = append(data, &xsoar.Incident{ID: id})
+			}
+			err = json.NewEncoder(w).Encode(map[string]any{"data": data})
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		report, err := client.Incidents.BatchDelete(ctx, xsoar.IncidentIDs(ids...))
+		var authErr *xsoar.AuthenticationError
+		require.ErrorAs(t, err, &authErr)
+		require.NotNil(t, report)
+		assert.Equal(t, 500, report.Succeeded)
+	})
+
+	t.Run("validates selection", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call for an invalid selection")
+		})
+
+		tests := []struct {
+			name      string
+			selection xsoar.IncidentSelection
+		}{
+			{"empty", xsoar.IncidentSelection{}},
+			{"empty filter", xsoar.IncidentsMatching(&xsoar.IncidentFilter{})},
+			{"IDs and filter", xsoar.IncidentSelection{IDs: []string{"inc-1"}, Filter: &xsoar.IncidentFilter{Query: "x"}}},
+		}
+
+		ctx := context.Background()
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := client.Incidents.BatchDelete(ctx, tt.selection)
+				var validationErr *xsoar.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+			})
+		}
+	})
+}
+
+func TestIncidentService_BatchClose(t *testing.T) {
+	t.Run("closes incidents matching a filter", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/incident/batchClose", r.URL.Path)
+
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+
+			assert.Equal(t, true, reqBody["all"])
+			assert.Equal(t, "False Positive", reqBody["closeReason"])
+			assert.Equal(t, "noisy rule", reqBody["closeNotes"])
+			assert.NotContains(t, reqBody, "ids")
+			filter, ok := reqBody["filter"].(map[string]any)
+			assert.True(t, ok)
+			assert.Equal(t, "name:noisy", filter["query"])
+
+			err = json.NewEncoder(w).Encode(map[string]any{
+				"data":       []*xsoar.Incident{{ID: "inc-1"}, {ID: "inc-2"}},
+				"total":      2,
+				"notUpdated": 1,
+			})
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		report, err := client.Incidents.BatchClose(ctx,
+			xsoar.IncidentsMatching(&xsoar.IncidentFilter{Query: "name:noisy"}),
+			&xsoar.CloseIncidentRequest{Reason: "False Positive", Notes: "noisy rule"})
+		require.NoError(t, err)
+
+		assert.Len(t, report.Results, 2)
+		assert.Equal(t, 2, report.Succeeded)
+		assert.Equal(t, 1, report.Failed)
+	})
+}
+
+func TestIncidentService_BatchUpdate(t *testing.T) {
+	t.Run("sends fields and custom fields", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/incident/batchUpdate", r.URL.Path)
+
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+
+			assert.Equal(t, []any{"inc-1"}, reqBody["ids"])
+			assert.Equal(t, map[string]any{"owner": "analyst", "severity": float64(4)}, reqBody["data"])
+			assert.Equal(t, map[string]any{"ticket": "JIRA-1"}, reqBody["customFields"])
+
+			err = json.NewEncoder(w).Encode(map[string]any{"data": []*xsoar.Incident{{ID: "inc-1", Owner: "analyst"}}})
+			assert.NoError(t, err)
+		})
+
+		owner := "analyst"
+		severity := xsoar.SeverityHigh
+		ctx := context.Background()
+		report, err := client.Incidents.BatchUpdate(ctx, xsoar.IncidentIDs("inc-1"), &xsoar.UpdateIncidentRequest{
+			Owner:        &owner,
+			Severity:     &severity,
+			CustomFields: map[string]any{"ticket": "JIRA-1"},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, report.Results, 1)
+		assert.Equal(t, "analyst", report.Results[0].Incident.Owner)
+	})
+
+	t.Run("requires update request", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call without an update request")
+		})
+
+		ctx := context.Background()
+		_, err := client.Incidents.BatchUpdate(ctx, xsoar.IncidentIDs("inc-1"), nil)
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
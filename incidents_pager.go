@@ -0,0 +1,218 @@
+package xsoar
+
+import (
+	"context"
+	"time"
+)
+
+// incidentPager fetches successive pages of incidents for Search.
+type incidentPager interface {
+	// next returns the next batch of incidents and whether more may follow.
+	next(ctx context.Context) (incidents []*Incident, more bool, err error)
+}
+
+// newPager returns the pager selected by the request options.
+func (s *incidentService) newPager(filter *IncidentFilter, reqCfg *requestConfig, opts []RequestOption) incidentPager {
+	if reqCfg.consistent {
+		return newWatermarkPager(s, filter, reqCfg, opts)
+	}
+	return &offsetPager{
+		service:  s,
+		filter:   stableSort(filter),
+		opts:     opts,
+		pageSize: reqCfg.pageLimit(),
+	}
+}
+
+// offsetPager pages through results by offset (fromIndex). It is cheap but
+// skips or repeats incidents if the result set changes during iteration.
+type offsetPager struct {
+	service  *incidentService
+	filter   *IncidentFilter
+	opts     []RequestOption
+	pageSize int
+	offset   int
+}
+
+func (p *offsetPager) next(ctx context.Context) ([]*Incident, bool, error) {
+	page, err := p.service.SearchPage(ctx, p.filter, &PageOptions{
+		Offset: p.offset,
+		Limit:  p.pageSize,
+	}, p.opts...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	p.offset = page.NextOffset()
+	return page.Data, page.HasMore(), nil
+}
+
+// watermarkPager pages through results ordered by a timestamp field, using
+// the timestamp of the last incident seen as a watermark for the next query
+// instead of an offset. Incidents added, removed or reordered during
+// iteration therefore cannot shift later pages. Incidents sharing the
+// watermark timestamp are tracked by ID so that overlapping pages are
+// de-duplicated.
+type watermarkPager struct {
+	service  *incidentService
+	filter   IncidentFilter
+	opts     []RequestOption
+	pageSize int
+
+	// timeOf returns the paged timestamp of an incident, and from restricts
+	// a filter to incidents at or after a watermark.
+	timeOf func(*Incident) time.Time
+	from   func(filter *IncidentFilter, watermark time.Time)
+
+	watermark time.Time
+	boundary  map[string]bool // IDs seen with timeOf == watermark
+	skip      int             // offset of the next page within the current bound
+}
+
+// newWatermarkPager returns a pager over incidents ordered by creation time,
+// starting at the filter's FromDate.
+func newWatermarkPager(s *incidentService, filter *IncidentFilter, reqCfg *requestConfig, opts []RequestOption) *watermarkPager {
+	p := newTimestampPager(s, filter, "created", reqCfg, opts)
+	p.timeOf = func(incident *Incident) time.Time { return incident.Created }
+	p.from = func(filter *IncidentFilter, watermark time.Time) {
+		filter.FromDate = watermark
+		if len(p.boundary) > 0 {
+			// The server may treat fromDate as exclusive. Start just before
+			// a watermark taken from an incident so that incidents sharing
+			// its timestamp are returned again and de-duplicated by ID.
+			filter.FromDate = watermark.Add(-time.Nanosecond)
+		}
+	}
+	p.watermark = p.filter.FromDate
+	return p
+}
+
+// newModifiedPager returns a pager over incidents ordered by modification
+// time, starting at since. It is used by Watch, where incidents modified
+// while paging move to the end of the result set.
+func newModifiedPager(s *incidentService, filter *IncidentFilter, since time.Time, reqCfg *requestConfig, opts []RequestOption) *watermarkPager {
+	p := newTimestampPager(s, filter, "modified", reqCfg, opts)
+	userQuery := p.filter.Query
+	p.timeOf = func(incident *Incident) time.Time { return incident.Modified }
+	p.from = func(filter *IncidentFilter, watermark time.Time) {
+		filter.Query = windowQuery(userQuery, watermark)
+	}
+	p.watermark = since
+	return p
+}
+
+func newTimestampPager(s *incidentService, filter *IncidentFilter, field string, reqCfg *requestConfig, opts []RequestOption) *watermarkPager {
+	p := &watermarkPager{
+		service:  s,
+		opts:     opts,
+		pageSize: reqCfg.pageLimit(),
+		boundary: make(map[string]bool),
+	}
+	if filter != nil {
+		p.filter = *filter
+	}
+	p.filter.Sort = []SortField{{Field: field, Asc: true}, {Field: "id", Asc: true}}
+
+	// The watermark needs the paged timestamp even when a projection is requested.
+	if len(reqCfg.fields) > 0 {
+		p.opts = append(p.opts[:len(p.opts):len(p.opts)], WithFields(field))
+	}
+	return p
+}
+
+func (p *watermarkPager) next(ctx context.Context) ([]*Incident, bool, error) {
+	filter := p.filter
+	p.from(&filter, p.watermark)
+
+	page, err := p.service.SearchPage(ctx, &filter, &PageOptions{
+		Offset: p.skip,
+		Limit:  p.pageSize,
+	}, p.opts...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	start := p.watermark
+	fresh := make([]*Incident, 0, len(page.Data))
+	for _, incident := range page.Data {
+		at := p.timeOf(incident)
+		if at.Before(p.watermark) || (at.Equal(p.watermark) && p.boundary[incident.ID]) {
+			continue
+		}
+		if at.After(p.watermark) {
+			p.watermark = at
+			clear(p.boundary)
+		}
+		p.boundary[incident.ID] = true
+		fresh = append(fresh, incident)
+	}
+
+	full := len(page.Data) >= p.pageSize
+	if p.watermark.Equal(start) {
+		// The page did not move the watermark, so the next query has the
+		// same bound; page past this one within it.
+		p.skip += len(page.Data)
+	} else {
+		// The next query starts at the new watermark.
+		p.skip = 0
+	}
+
+	return fresh, full, nil
+}
+
+// pageResult is the outcome of one background page fetch.
+type pageResult struct {
+	incidents []*Incident
+	more      bool
+	err       error
+}
+
+// prefetchPager reads pages from a background goroutine that fetches the
+// next page while the current one is being consumed.
+type prefetchPager struct {
+	results <-chan pageResult
+}
+
+// prefetch runs pager in the background, one page ahead of the consumer.
+// The returned stop function cancels any in-flight request and waits for
+// the background goroutine to exit; it must be called once iteration ends.
+func prefetch(ctx context.Context, pager incidentPager) (incidentPager, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	results := make(chan pageResult)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer close(results)
+
+		for {
+			incidents, more, err := pager.next(ctx)
+			select {
+			case results <- pageResult{incidents: incidents, more: more, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil || !more {
+				return
+			}
+		}
+	}()
+
+	stop := func() {
+		cancel()
+		<-done
+	}
+	return &prefetchPager{results: results}, stop
+}
+
+func (p *prefetchPager) next(ctx context.Context) ([]*Incident, bool, error) {
+	select {
+	case result, ok := <-p.results:
+		if !ok {
+			return nil, false, ctx.Err()
+		}
+		return result.incidents, result.more, result.err
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+}
@@ -1,10 +1,17 @@
 package xsoar_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,15 +20,15 @@ import (
 	"github.com/tphakala/go-xsoar"
 )
 
-func setupTestServer(t *testing.T, handler http.HandlerFunc) *xsoar.Client {
+func setupTestServer(t *testing.T, handler http.HandlerFunc, opts ...xsoar.ClientOption) *xsoar.Client {
 	t.Helper()
 	server := httptest.NewServer(handler)
 	t.Cleanup(server.Close)
 
-	client, err := xsoar.NewClient(
+	client, err := xsoar.NewClient(append([]xsoar.ClientOption{
 		xsoar.WithBaseURL(server.URL),
 		xsoar.WithAPIKey("test-key-id", "test-api-key"),
-	)
+	}, opts...)...)
 	require.NoError(t, err)
 
 	return client
@@ -222,6 +229,90 @@ func TestIncidentService_Search(t *testing.T) {
 	})
 }
 
+func TestIncidentService_SortAndProjection(t *testing.T) {
+	t.Run("search appends id tiebreaker", func(t *testing.T) {
+		var sorts []any
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+
+			filter, ok := reqBody["filter"].(map[string]any)
+			assert.True(t, ok, "filter should be a map")
+			sorts = append(sorts, filter["sort"])
+
+			err = json.NewEncoder(w).Encode(xsoar.IncidentPage{Data: []*xsoar.Incident{}, Total: 0})
+			assert.NoError(t, err)
+		})
+
+		filter := &xsoar.IncidentFilter{
+			Sort: []xsoar.SortField{{Field: "created", Asc: false}},
+		}
+		_, err := xsoar.Collect(client.Incidents.Search(context.Background(), filter))
+		require.NoError(t, err)
+
+		require.Len(t, sorts, 1)
+		assert.Equal(t, []any{
+			map[string]any{"field": "created", "asc": false},
+			map[string]any{"field": "id", "asc": true},
+		}, sorts[0])
+		assert.Len(t, filter.Sort, 1, "caller's filter must not be modified")
+	})
+
+	t.Run("existing id sort is kept", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var reqBody struct {
+				Filter xsoar.IncidentFilter `json:"filter"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, []xsoar.SortField{{Field: "id", Asc: false}}, reqBody.Filter.Sort)
+
+			err = json.NewEncoder(w).Encode(xsoar.IncidentPage{Data: []*xsoar.Incident{}, Total: 0})
+			assert.NoError(t, err)
+		})
+
+		_, err := xsoar.Collect(client.Incidents.Search(context.Background(), &xsoar.IncidentFilter{
+			Sort: []xsoar.SortField{{Field: "id", Asc: false}},
+		}))
+		require.NoError(t, err)
+	})
+
+	t.Run("projection always includes id", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, []any{"name", "severity", "id"}, reqBody["fields"])
+
+			err = json.NewEncoder(w).Encode(xsoar.IncidentPage{
+				Data:  []*xsoar.Incident{{ID: "inc-1", Name: "Test"}},
+				Total: 1,
+			})
+			assert.NoError(t, err)
+		})
+
+		page, err := client.Incidents.SearchPage(context.Background(), nil, nil, xsoar.WithFields("name", "severity"))
+		require.NoError(t, err)
+		assert.Equal(t, "Test", page.Data[0].Name)
+	})
+
+	t.Run("no projection by default", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.NotContains(t, reqBody, "fields")
+
+			err = json.NewEncoder(w).Encode(xsoar.IncidentPage{Data: []*xsoar.Incident{}, Total: 0})
+			assert.NoError(t, err)
+		})
+
+		_, err := client.Incidents.SearchPage(context.Background(), nil, nil)
+		require.NoError(t, err)
+	})
+}
+
 func TestIncidentService_Get(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
@@ -535,6 +626,45 @@ func TestResponseSizeLimit(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "response too large")
 	})
+
+	t.Run("honors configured limit", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write(bytes.Repeat([]byte("x"), 2048))
+			assert.NoError(t, err)
+		}))
+		t.Cleanup(server.Close)
+
+		client, err := xsoar.NewClient(
+			xsoar.WithBaseURL(server.URL),
+			xsoar.WithAPIKey("test-key-id", "test-api-key"),
+			xsoar.WithMaxResponseSize(1024),
+		)
+		require.NoError(t, err)
+
+		_, err = client.Incidents.Get(context.Background(), "test-id")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds 1024 bytes")
+	})
+
+	t.Run("allows responses above default when raised", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name := strings.Repeat("x", 11*1024*1024)
+			err := json.NewEncoder(w).Encode(xsoar.Incident{ID: "inc-1", Name: name})
+			assert.NoError(t, err)
+		}))
+		t.Cleanup(server.Close)
+
+		client, err := xsoar.NewClient(
+			xsoar.WithBaseURL(server.URL),
+			xsoar.WithAPIKey("test-key-id", "test-api-key"),
+			xsoar.WithMaxResponseSize(20*1024*1024),
+		)
+		require.NoError(t, err)
+
+		incident, err := client.Incidents.Get(context.Background(), "inc-1")
+		require.NoError(t, err)
+		assert.Len(t, incident.Name, 11*1024*1024)
+	})
 }
 
 func TestURLEncoding(t *testing.T) {
@@ -556,3 +686,646 @@ func TestURLEncoding(t *testing.T) {
 		assert.Equal(t, "/incident/inc%2Ftest%3Fid=123", receivedRawPath)
 	})
 }
+
+func TestIncidentService_UploadAttachment(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/incident/upload/inc-123", r.URL.Path)
+			assert.Equal(t, "test-api-key", r.Header.Get("Authorization"))
+
+			err := r.ParseMultipartForm(1 << 20)
+			assert.NoError(t, err)
+			assert.Equal(t, "attachment", r.FormValue("field"))
+			assert.Equal(t, "phish.eml", r.FormValue("fileName"))
+
+			file, header, err := r.FormFile("file")
+			assert.NoError(t, err)
+			defer func() { _ = file.Close() }()
+			assert.Equal(t, "phish.eml", header.Filename)
+			content, err := io.ReadAll(file)
+			assert.NoError(t, err)
+			assert.Equal(t, "From: attacker@example.com", string(content))
+
+			err = json.NewEncoder(w).Encode(xsoar.Incident{
+				ID:          "inc-123",
+				Attachments: []xsoar.Attachment{{Name: "phish.eml", Path: "123_phish.eml"}},
+			})
+			assert.NoError(t, err)
+		})
+
+		incident, err := client.Incidents.UploadAttachment(context.Background(), "inc-123", "", "phish.eml",
+			bytes.NewReader([]byte("From: attacker@example.com")))
+		require.NoError(t, err)
+
+		require.Len(t, incident.Attachments, 1)
+		assert.Equal(t, "123_phish.eml", incident.Attachments[0].Path)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		_, err := client.Incidents.UploadAttachment(context.Background(), "missing", "", "a.txt",
+			bytes.NewReader([]byte("data")))
+
+		var notFoundErr *xsoar.NotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "incident", notFoundErr.ResourceType)
+	})
+
+	t.Run("missing filename returns validation error", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call without filename")
+		})
+
+		_, err := client.Incidents.UploadAttachment(context.Background(), "inc-123", "", "",
+			bytes.NewReader([]byte("data")))
+
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestIncidentService_DownloadAttachment(t *testing.T) {
+	t.Run("streams content beyond buffered size limit", func(t *testing.T) {
+		const size = 12 * 1024 * 1024
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/entry/download/123_phish.eml", r.URL.Path)
+
+			_, err := w.Write(bytes.Repeat([]byte("x"), size))
+			assert.NoError(t, err)
+		})
+
+		body, err := client.Incidents.DownloadAttachment(context.Background(), "123_phish.eml")
+		require.NoError(t, err)
+		defer func() { _ = body.Close() }()
+
+		n, err := io.Copy(io.Discard, body)
+		require.NoError(t, err)
+		assert.Equal(t, int64(size), n)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		_, err := client.Incidents.DownloadAttachment(context.Background(), "missing")
+
+		var notFoundErr *xsoar.NotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "attachment", notFoundErr.ResourceType)
+		assert.Equal(t, "missing", notFoundErr.ResourceID)
+	})
+}
+
+func TestIncidentService_RemoveAttachment(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/incident/remove/inc-123", r.URL.Path)
+
+		var reqBody map[string]any
+		err := json.NewDecoder(r.Body).Decode(&reqBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "evidencefiles", reqBody["fieldName"])
+		assert.Equal(t, []any{"123_phish.eml"}, reqBody["files"])
+	})
+
+	err := client.Incidents.RemoveAttachment(context.Background(), "inc-123", "evidencefiles", "123_phish.eml")
+	require.NoError(t, err)
+}
+
+// incidentStore serves watermark searches over an in-memory incident set
+// ordered by creation time and ID.
+type incidentStore struct {
+	mu        sync.Mutex
+	incidents []*xsoar.Incident
+	requests  []searchCall
+
+	// exclusive makes fromDate exclude incidents created at exactly that time.
+	exclusive bool
+}
+
+type searchCall struct {
+	fromDate  time.Time
+	fromIndex int
+	sort      []xsoar.SortField
+}
+
+func (s *incidentStore) handler(t *testing.T, afterPage func(s *incidentStore, call int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Filter    xsoar.IncidentFilter `json:"filter"`
+			FromIndex int                  `json:"fromIndex"`
+			Size      int                  `json:"size"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&reqBody)
+		assert.NoError(t, err)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, searchCall{
+			fromDate:  reqBody.Filter.FromDate,
+			fromIndex: reqBody.FromIndex,
+			sort:      reqBody.Filter.Sort,
+		})
+
+		var matched []*xsoar.Incident
+		for _, incident := range s.incidents {
+			if incident.Created.After(reqBody.Filter.FromDate) ||
+				(!s.exclusive && incident.Created.Equal(reqBody.Filter.FromDate)) {
+				matched = append(matched, incident)
+			}
+		}
+		start := min(reqBody.FromIndex, len(matched))
+		end := min(start+reqBody.Size, len(matched))
+
+		err = json.NewEncoder(w).Encode(xsoar.IncidentPage{
+			Data:  matched[start:end],
+			Total: len(matched),
+		})
+		assert.NoError(t, err)
+
+		if afterPage != nil {
+			afterPage(s, len(s.requests))
+		}
+	}
+}
+
+func TestIncidentService_SearchConsistent(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("tolerates incidents closed and created during iteration", func(t *testing.T) {
+		store := &incidentStore{}
+		for i := range 150 {
+			store.incidents = append(store.incidents, &xsoar.Incident{
+				ID:      fmt.Sprintf("inc-%03d", i),
+				Created: base.Add(time.Duration(i) * time.Minute),
+			})
+		}
+
+		client := setupTestServer(t, store.handler(t, func(s *incidentStore, call int) {
+			if call == 1 {
+				// An already returned incident leaves the result set and a new one arrives.
+				s.incidents = append(s.incidents[1:], &xsoar.Incident{
+					ID:      "inc-new",
+					Created: base.Add(time.Hour * 24),
+				})
+			}
+		}))
+
+		ctx := context.Background()
+		incidents, err := xsoar.Collect(client.Incidents.Search(ctx, nil, xsoar.WithConsistentIteration()))
+		require.NoError(t, err)
+
+		seen := make(map[string]bool)
+		for _, incident := range incidents {
+			assert.False(t, seen[incident.ID], "duplicate incident %s", incident.ID)
+			seen[incident.ID] = true
+		}
+		assert.Len(t, incidents, 151)
+		assert.True(t, seen["inc-000"])
+		assert.True(t, seen["inc-149"])
+		assert.True(t, seen["inc-new"])
+
+		require.NotEmpty(t, store.requests)
+		assert.Equal(t, []xsoar.SortField{{Field: "created", Asc: true}, {Field: "id", Asc: true}}, store.requests[0].sort)
+		assert.Equal(t, base.Add(99*time.Minute-time.Nanosecond), store.requests[1].fromDate)
+		assert.Zero(t, store.requests[1].fromIndex)
+	})
+
+	t.Run("pages through incidents sharing a timestamp", func(t *testing.T) {
+		store := &incidentStore{}
+		for i := range 250 {
+			store.incidents = append(store.incidents, &xsoar.Incident{
+				ID:      fmt.Sprintf("inc-%03d", i),
+				Created: base,
+			})
+		}
+		client := setupTestServer(t, store.handler(t, nil))
+
+		ctx := context.Background()
+		incidents, err := xsoar.Collect(client.Incidents.Search(ctx, nil, xsoar.WithConsistentIteration()))
+		require.NoError(t, err)
+
+		require.Len(t, incidents, 250)
+		assert.Equal(t, "inc-249", incidents[249].ID)
+		fromIndexes := make([]int, 0, len(store.requests))
+		for _, call := range store.requests {
+			fromIndexes = append(fromIndexes, call.fromIndex)
+		}
+		assert.Equal(t, []int{0, 0, 100, 200}, fromIndexes)
+	})
+
+	t.Run("does not depend on fromDate being inclusive", func(t *testing.T) {
+		for _, exclusive := range []bool{false, true} {
+			// Groups of three incidents share a creation time, so page
+			// boundaries split a group.
+			store := &incidentStore{exclusive: exclusive}
+			for i := range 150 {
+				store.incidents = append(store.incidents, &xsoar.Incident{
+					ID:      fmt.Sprintf("inc-%03d", i),
+					Created: base.Add(time.Duration(i/3+1) * time.Minute),
+				})
+			}
+			client := setupTestServer(t, store.handler(t, nil))
+
+			ctx := context.Background()
+			incidents, err := xsoar.Collect(client.Incidents.Search(ctx, nil, xsoar.WithConsistentIteration()))
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(incidents))
+			for _, incident := range incidents {
+				ids = append(ids, incident.ID)
+			}
+			assert.Len(t, slices.Compact(ids), 150, "exclusive=%v", exclusive)
+		}
+	})
+
+	t.Run("starts at filter FromDate and keeps created in projection", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+
+			filter, ok := reqBody["filter"].(map[string]any)
+			assert.True(t, ok)
+			assert.Equal(t, "2026-01-01T00:00:00Z", filter["fromDate"])
+			assert.Contains(t, reqBody["fields"], "created")
+			assert.Contains(t, reqBody["fields"], "name")
+
+			err = json.NewEncoder(w).Encode(xsoar.IncidentPage{})
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		filter := &xsoar.IncidentFilter{FromDate: base}
+		_, err := xsoar.Collect(client.Incidents.Search(ctx, filter,
+			xsoar.WithConsistentIteration(), xsoar.WithFields("name")))
+		require.NoError(t, err)
+		assert.Empty(t, filter.Sort, "caller's filter should not be modified")
+	})
+}
+
+func TestIncidentService_SearchPageSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		expected float64
+	}{
+		{"custom size", 250, 250},
+		{"capped at maximum", 5000, 1000},
+		{"default for zero", 0, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				var reqBody map[string]any
+				err := json.NewDecoder(r.Body).Decode(&reqBody)
+				assert.NoError(t, err)
+				assert.InDelta(t, tt.expected, reqBody["size"], 0)
+
+				err = json.NewEncoder(w).Encode(xsoar.IncidentPage{})
+				assert.NoError(t, err)
+			})
+
+			ctx := context.Background()
+			_, err := xsoar.Collect(client.Incidents.Search(ctx, nil, xsoar.WithPageSize(tt.size)))
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestIncidentService_SearchPrefetch(t *testing.T) {
+	t.Run("iterates all pages", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+
+			fromIndex, ok := reqBody["fromIndex"].(float64)
+			assert.True(t, ok, "fromIndex should be a number")
+			offset := int(fromIndex)
+
+			var data []*xsoar.Incident
+			for i := offset; i < min(offset+2, 5); i++ {
+				data = append(data, &xsoar.Incident{ID: fmt.Sprintf("inc-%d", i)})
+			}
+			err = json.NewEncoder(w).Encode(xsoar.IncidentPage{Data: data, Total: 5, Offset: offset})
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		incidents, err := xsoar.Collect(client.Incidents.Search(ctx, nil,
+			xsoar.WithPageSize(2), xsoar.WithPrefetch()))
+		require.NoError(t, err)
+
+		require.Len(t, incidents, 5)
+		for i, incident := range incidents {
+			assert.Equal(t, fmt.Sprintf("inc-%d", i), incident.ID)
+		}
+	})
+
+	t.Run("fetches next page while consuming and cancels on break", func(t *testing.T) {
+		secondPage := make(chan struct{})
+		canceled := make(chan struct{})
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+
+			if reqBody["fromIndex"] != float64(0) {
+				close(secondPage)
+				<-r.Context().Done()
+				close(canceled)
+				return
+			}
+			err = json.NewEncoder(w).Encode(xsoar.IncidentPage{
+				Data:  []*xsoar.Incident{{ID: "inc-1"}, {ID: "inc-2"}},
+				Total: 10,
+			})
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		for incident, err := range client.Incidents.Search(ctx, nil, xsoar.WithPageSize(2), xsoar.WithPrefetch()) {
+			require.NoError(t, err)
+			assert.Equal(t, "inc-1", incident.ID)
+
+			select {
+			case <-secondPage:
+			case <-time.After(5 * time.Second):
+				t.Fatal("next page was not requested while consuming the first")
+			}
+			break
+		}
+
+		select {
+		case <-canceled:
+		case <-time.After(5 * time.Second):
+			t.Fatal("in-flight request was not canceled after break")
+		}
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		})
+
+		ctx := context.Background()
+		_, err := xsoar.Collect(client.Incidents.Search(ctx, nil, xsoar.WithPrefetch()))
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestIncidentService_UpdateVersion(t *testing.T) {
+	t.Run("sends version", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.InDelta(t, 7, reqBody["version"], 0)
+			w.WriteHeader(http.StatusOK)
+		})
+
+		version := 7
+		ctx := context.Background()
+		err := client.Incidents.Update(ctx, "inc-1", &xsoar.UpdateIncidentRequest{Version: &version})
+		require.NoError(t, err)
+	})
+
+	t.Run("stale version returns ConflictError", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+			_, err := w.Write([]byte(`{"message":"incident was modified"}`))
+			assert.NoError(t, err)
+		})
+
+		version := 7
+		ctx := context.Background()
+		err := client.Incidents.Update(ctx, "inc-1", &xsoar.UpdateIncidentRequest{Version: &version})
+		var conflictErr *xsoar.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, "incident was modified", conflictErr.Message)
+	})
+}
+
+func TestIncidentService_UpdateWithRetry(t *testing.T) {
+	t.Run("retries on conflict with a fresh copy", func(t *testing.T) {
+		var mu sync.Mutex
+		version := 1
+		updates := 0
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch r.URL.Path {
+			case "/incident/inc-1":
+				err := json.NewEncoder(w).Encode(xsoar.Incident{
+					ID:           "inc-1",
+					Version:      version,
+					CustomFields: map[string]any{"count": float64(version)},
+				})
+				assert.NoError(t, err)
+			case "/incident/update":
+				var incident xsoar.Incident
+				err := json.NewDecoder(r.Body).Decode(&incident)
+				assert.NoError(t, err)
+
+				updates++
+				if updates == 1 {
+					// Another writer got there first.
+					version++
+					w.WriteHeader(http.StatusConflict)
+					return
+				}
+				assert.Equal(t, version, incident.Version)
+				assert.Equal(t, "done", incident.CustomFields["state"])
+
+				version++
+				incident.Version = version
+				err = json.NewEncoder(w).Encode(incident)
+				assert.NoError(t, err)
+			}
+		})
+
+		var seen []float64
+		ctx := context.Background()
+		incident, err := client.Incidents.UpdateWithRetry(ctx, "inc-1", func(incident *xsoar.Incident) error {
+			count, ok := incident.CustomFields["count"].(float64)
+			assert.True(t, ok)
+			seen = append(seen, count)
+			incident.CustomFields["state"] = "done"
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []float64{1, 2}, seen)
+		assert.Equal(t, 3, incident.Version)
+		assert.Equal(t, 2, updates)
+	})
+
+	t.Run("gives up after repeated conflicts", func(t *testing.T) {
+		updates := 0
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/incident/update" {
+				updates++
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			err := json.NewEncoder(w).Encode(xsoar.Incident{ID: "inc-1", Version: 1})
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		_, err := client.Incidents.UpdateWithRetry(ctx, "inc-1", func(*xsoar.Incident) error { return nil })
+		var conflictErr *xsoar.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, 5, updates)
+	})
+
+	t.Run("mutate error aborts", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/incident/update" {
+				t.Error("should not update when mutate fails")
+			}
+			err := json.NewEncoder(w).Encode(xsoar.Incident{ID: "inc-1", Version: 1})
+			assert.NoError(t, err)
+		})
+
+		errAbort := errors.New("abort")
+		ctx := context.Background()
+		_, err := client.Incidents.UpdateWithRetry(ctx, "inc-1", func(*xsoar.Incident) error { return errAbort })
+		require.ErrorIs(t, err, errAbort)
+	})
+}
+
+func TestIncidentService_Investigate(t *testing.T) {
+	t.Run("returns investigation ID", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/incident/investigate", r.URL.Path)
+
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, "inc-1", reqBody["id"])
+
+			_, err = w.Write([]byte(`{"id":"inv-1"}`))
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		investigationID, err := client.Incidents.Investigate(ctx, "inc-1")
+		require.NoError(t, err)
+		assert.Equal(t, "inv-1", investigationID)
+	})
+
+	t.Run("defaults to incident ID", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(`{}`))
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		investigationID, err := client.Incidents.Investigate(ctx, "inc-1")
+		require.NoError(t, err)
+		assert.Equal(t, "inc-1", investigationID)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		ctx := context.Background()
+		_, err := client.Incidents.Investigate(ctx, "inc-404")
+		var notFoundErr *xsoar.NotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "inc-404", notFoundErr.ResourceID)
+	})
+}
+
+func TestIncidentService_Reopen(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/incident/reopen", r.URL.Path)
+
+		var reqBody map[string]any
+		err := json.NewDecoder(r.Body).Decode(&reqBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "inc-1", reqBody["id"])
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ctx := context.Background()
+	err := client.Incidents.Reopen(ctx, "inc-1")
+	require.NoError(t, err)
+
+	err = client.Incidents.Reopen(ctx, "")
+	var validationErr *xsoar.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func TestIncidentService_SetPlaybook(t *testing.T) {
+	t.Run("starts investigation when needed", func(t *testing.T) {
+		var paths []string
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.EscapedPath())
+			switch r.URL.Path {
+			case "/incident/inc-1":
+				err := json.NewEncoder(w).Encode(xsoar.Incident{ID: "inc-1"})
+				assert.NoError(t, err)
+			case "/incident/investigate":
+				_, err := w.Write([]byte(`{"id":"inc-1"}`))
+				assert.NoError(t, err)
+			default:
+				w.WriteHeader(http.StatusOK)
+			}
+		})
+
+		ctx := context.Background()
+		investigationID, err := client.Incidents.SetPlaybook(ctx, "inc-1", "playbook 1")
+		require.NoError(t, err)
+
+		assert.Equal(t, "inc-1", investigationID)
+		assert.Equal(t, []string{
+			"/incident/inc-1",
+			"/incident/investigate",
+			"/inv-playbook/new/playbook%201/inc-1",
+		}, paths)
+	})
+
+	t.Run("uses existing investigation", func(t *testing.T) {
+		var paths []string
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			if r.URL.Path == "/incident/inc-1" {
+				err := json.NewEncoder(w).Encode(xsoar.Incident{ID: "inc-1", InvestigateID: "inv-9"})
+				assert.NoError(t, err)
+			}
+		})
+
+		ctx := context.Background()
+		investigationID, err := client.Incidents.SetPlaybook(ctx, "inc-1", "triage-v2")
+		require.NoError(t, err)
+
+		assert.Equal(t, "inv-9", investigationID)
+		assert.Equal(t, []string{"/incident/inc-1", "/inv-playbook/new/triage-v2/inv-9"}, paths)
+	})
+
+	t.Run("requires playbook ID", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call without a playbook ID")
+		})
+
+		ctx := context.Background()
+		_, err := client.Incidents.SetPlaybook(ctx, "inc-1", "")
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
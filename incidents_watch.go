@@ -0,0 +1,210 @@
+package xsoar
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"time"
+
+	"github.com/tphakala/go-xsoar/query"
+)
+
+// Default change-feed settings.
+const (
+	defaultWatchInterval = time.Minute
+	defaultClockSkew     = 30 * time.Second
+)
+
+// CheckpointStore persists the state of Incidents.Watch so that a restarted
+// process resumes where it left off instead of missing or replaying changes.
+type CheckpointStore interface {
+	// Load returns the saved checkpoint, or nil if none has been saved yet.
+	Load(ctx context.Context) (*WatchCheckpoint, error)
+
+	// Save stores the checkpoint, replacing any previous one.
+	Save(ctx context.Context, checkpoint *WatchCheckpoint) error
+}
+
+// incidentWatcher polls for incidents modified since a watermark.
+type incidentWatcher struct {
+	service *incidentService
+	filter  IncidentFilter
+	opts    []RequestOption
+	reqCfg  *requestConfig
+	skew    time.Duration
+	store   CheckpointStore
+
+	checkpoint WatchCheckpoint
+}
+
+// Watch returns an iterator over changes to incidents matching the filter.
+func (s *incidentService) Watch(ctx context.Context, filter *IncidentFilter, interval time.Duration, opts ...RequestOption) iter.Seq2[IncidentEvent, error] {
+	return func(yield func(IncidentEvent, error) bool) {
+		reqCfg := newRequestConfig()
+		reqCfg.apply(opts...)
+
+		if interval <= 0 {
+			interval = defaultWatchInterval
+		}
+
+		// Events are classified from these fields, so they are needed even
+		// when a projection is requested.
+		if len(reqCfg.fields) > 0 {
+			opts = append(opts[:len(opts):len(opts)], WithFields("created", "closed", "status"))
+		}
+
+		w := &incidentWatcher{
+			service: s,
+			opts:    opts,
+			reqCfg:  reqCfg,
+			skew:    reqCfg.clockSkew,
+			store:   reqCfg.checkpoints,
+		}
+		if filter != nil {
+			w.filter = *filter
+		}
+		if w.skew <= 0 {
+			w.skew = defaultClockSkew
+		}
+
+		if err := w.load(ctx); err != nil {
+			yield(IncidentEvent{}, err)
+			return
+		}
+
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			more, err := w.poll(ctx, yield)
+			if !more {
+				return
+			}
+			if err != nil && (ctx.Err() != nil || !yield(IncidentEvent{}, err)) {
+				return
+			}
+			timer.Reset(interval)
+		}
+	}
+}
+
+// load restores the checkpoint from the store. Without a saved checkpoint,
+// the watch starts at the current time.
+func (w *incidentWatcher) load(ctx context.Context) error {
+	w.checkpoint = WatchCheckpoint{Watermark: time.Now()}
+	if w.store != nil {
+		saved, err := w.store.Load(ctx)
+		if err != nil {
+			return err
+		}
+		if saved != nil {
+			w.checkpoint.Watermark = saved.Watermark
+			w.checkpoint.Seen = maps.Clone(saved.Seen)
+		}
+	}
+	if w.checkpoint.Seen == nil {
+		w.checkpoint.Seen = make(map[string]time.Time)
+	}
+	return nil
+}
+
+// poll fetches incidents modified since the watermark, less the clock skew,
+// and yields the changes not delivered before. It returns false if the
+// consumer stopped iterating.
+//
+// Pages are fetched by modification time rather than by offset: an incident
+// modified again while the poll runs moves to the end of the result set,
+// which would shift an offset-based page past incidents not yet delivered.
+// Options that change the order, such as WithConsistentIteration, are ignored.
+func (w *incidentWatcher) poll(ctx context.Context, yield func(IncidentEvent, error) bool) (bool, error) {
+	since := w.checkpoint.Watermark.Add(-w.skew)
+	pager := newModifiedPager(w.service, &w.filter, since, w.reqCfg, w.opts)
+
+	for {
+		incidents, more, err := pager.next(ctx)
+		if err != nil {
+			return true, err
+		}
+		for _, incident := range incidents {
+			if !w.deliver(ctx, incident, since, yield) {
+				return false, nil
+			}
+		}
+		if !more {
+			return true, w.save(ctx)
+		}
+	}
+}
+
+// deliver yields the change to incident unless it was delivered before. It
+// returns false if the consumer stopped iterating.
+func (w *incidentWatcher) deliver(ctx context.Context, incident *Incident, since time.Time, yield func(IncidentEvent, error) bool) bool {
+	// Changes before the window were delivered by an earlier poll and
+	// may no longer be tracked for de-duplication.
+	if incident.Modified.Before(since) {
+		return true
+	}
+	last, known := w.checkpoint.Seen[incident.ID]
+	if known && !incident.Modified.After(last) {
+		return true
+	}
+
+	event := IncidentEvent{Type: classify(incident, known, since), Incident: incident}
+	w.checkpoint.Seen[incident.ID] = incident.Modified
+	if incident.Modified.After(w.checkpoint.Watermark) {
+		w.checkpoint.Watermark = incident.Modified
+	}
+
+	if !yield(event, nil) {
+		_ = w.save(ctx)
+		return false
+	}
+	return true
+}
+
+// save prunes de-duplication state outside the skew window and persists
+// the checkpoint.
+func (w *incidentWatcher) save(ctx context.Context) error {
+	cutoff := w.checkpoint.Watermark.Add(-w.skew)
+	maps.DeleteFunc(w.checkpoint.Seen, func(_ string, modified time.Time) bool {
+		return modified.Before(cutoff)
+	})
+
+	if w.store == nil {
+		return nil
+	}
+	checkpoint := WatchCheckpoint{
+		Watermark: w.checkpoint.Watermark,
+		Seen:      maps.Clone(w.checkpoint.Seen),
+	}
+	return w.store.Save(ctx, &checkpoint)
+}
+
+// classify derives the event type of a change. Incidents closed within the
+// polled window are reported as closed; incidents created within it and not
+// delivered before are reported as created.
+func classify(incident *Incident, known bool, since time.Time) IncidentEventType {
+	switch {
+	case incident.Status == StatusDone && !incident.Closed.Before(since):
+		return IncidentClosed
+	case !known && !incident.Created.Before(since):
+		return IncidentCreated
+	default:
+		return IncidentModified
+	}
+}
+
+// windowQuery restricts a query to incidents modified at or after since.
+func windowQuery(existing string, since time.Time) string {
+	window := query.Field("modified").GTE(since)
+	if existing == "" {
+		return window.String()
+	}
+	return query.And(window, query.Raw("("+existing+")")).String()
+}
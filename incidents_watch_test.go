@@ -0,0 +1,300 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+// memoryCheckpoints is an in-memory CheckpointStore.
+type memoryCheckpoints struct {
+	mu         sync.Mutex
+	checkpoint *xsoar.WatchCheckpoint
+	saves      int
+}
+
+func (m *memoryCheckpoints) Load(context.Context) (*xsoar.WatchCheckpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.checkpoint, nil
+}
+
+func (m *memoryCheckpoints) Save(_ context.Context, checkpoint *xsoar.WatchCheckpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checkpoint = checkpoint
+	m.saves++
+	return nil
+}
+
+func TestIncidentService_Watch(t *testing.T) {
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("yields each change once across overlapping polls", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			polls   int
+			queries []string
+		)
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var reqBody struct {
+				Filter xsoar.IncidentFilter `json:"filter"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			polls++
+			queries = append(queries, reqBody.Filter.Query)
+			poll := polls
+			mu.Unlock()
+
+			incidents := []*xsoar.Incident{
+				{ID: "inc-1", Created: base.Add(time.Minute), Modified: base.Add(time.Minute)},
+				{ID: "inc-2", Created: base.Add(-time.Hour), Modified: base.Add(2 * time.Minute)},
+				{
+					ID: "inc-3", Status: xsoar.StatusDone, Created: base.Add(-time.Hour),
+					Modified: base.Add(3 * time.Minute), Closed: base.Add(3 * time.Minute),
+				},
+			}
+			if poll > 1 {
+				incidents = append(incidents[1:], &xsoar.Incident{
+					ID: "inc-1", Created: base.Add(time.Minute), Modified: base.Add(4 * time.Minute),
+				})
+			}
+
+			err = json.NewEncoder(w).Encode(xsoar.IncidentPage{Data: incidents, Total: len(incidents)})
+			assert.NoError(t, err)
+		})
+
+		store := &memoryCheckpoints{checkpoint: &xsoar.WatchCheckpoint{Watermark: base}}
+		filter := &xsoar.IncidentFilter{Query: "type:Phishing"}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var events []xsoar.IncidentEvent
+		for event, err := range client.Incidents.Watch(ctx, filter, 10*time.Millisecond, xsoar.WithCheckpoint(store)) {
+			require.NoError(t, err)
+			events = append(events, event)
+			if len(events) == 4 {
+				break
+			}
+		}
+
+		require.Len(t, events, 4)
+		assert.Equal(t, xsoar.IncidentCreated, events[0].Type)
+		assert.Equal(t, "inc-1", events[0].Incident.ID)
+		assert.Equal(t, xsoar.IncidentModified, events[1].Type)
+		assert.Equal(t, "inc-2", events[1].Incident.ID)
+		assert.Equal(t, xsoar.IncidentClosed, events[2].Type)
+		assert.Equal(t, "inc-3", events[2].Incident.ID)
+		assert.Equal(t, xsoar.IncidentModified, events[3].Type)
+		assert.Equal(t, "inc-1", events[3].Incident.ID)
+
+		assert.Equal(t, `modified:>="2026-01-01T09:59:30Z" and (type:Phishing)`, queries[0])
+		assert.Empty(t, filter.Sort, "caller's filter should not be modified")
+
+		require.NotNil(t, store.checkpoint)
+		assert.Equal(t, base.Add(4*time.Minute), store.checkpoint.Watermark)
+		assert.Contains(t, store.checkpoint.Seen, "inc-1")
+		assert.NotContains(t, store.checkpoint.Seen, "inc-2", "changes outside the skew window are pruned")
+	})
+
+	t.Run("continues after errors", func(t *testing.T) {
+		var (
+			mu    sync.Mutex
+			polls int
+		)
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			polls++
+			poll := polls
+			mu.Unlock()
+
+			if poll == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			err := json.NewEncoder(w).Encode(xsoar.IncidentPage{
+				Data:  []*xsoar.Incident{{ID: "inc-1", Created: time.Now(), Modified: time.Now()}},
+				Total: 1,
+			})
+			assert.NoError(t, err)
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var errs []error
+		for event, err := range client.Incidents.Watch(ctx, nil, 10*time.Millisecond) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			assert.Equal(t, "inc-1", event.Incident.ID)
+			break
+		}
+
+		require.Len(t, errs, 1)
+		var validationErr *xsoar.ValidationError
+		assert.ErrorAs(t, errs[0], &validationErr)
+	})
+
+	t.Run("ends when context is canceled", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			err := json.NewEncoder(w).Encode(xsoar.IncidentPage{})
+			assert.NoError(t, err)
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		for _, err := range client.Incidents.Watch(ctx, nil, 10*time.Millisecond) {
+			require.NoError(t, err)
+		}
+		assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	})
+
+	t.Run("returns checkpoint load errors", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call when the checkpoint cannot be loaded")
+		})
+
+		ctx := context.Background()
+		_, err := xsoar.First(client.Incidents.Watch(ctx, nil, time.Second, xsoar.WithCheckpoint(failingCheckpoints{})))
+		require.ErrorIs(t, err, errCheckpoint)
+	})
+}
+
+var errCheckpoint = errors.New("checkpoint unavailable")
+
+type failingCheckpoints struct{}
+
+func (failingCheckpoints) Load(context.Context) (*xsoar.WatchCheckpoint, error) {
+	return nil, errCheckpoint
+}
+
+func (failingCheckpoints) Save(context.Context, *xsoar.WatchCheckpoint) error {
+	return errCheckpoint
+}
+
+// modifiedStore serves watch searches over an in-memory incident set,
+// honoring the modified window of the query and ordering by modification
+// time and ID like the server.
+type modifiedStore struct {
+	mu        sync.Mutex
+	incidents []*xsoar.Incident
+	sorts     [][]xsoar.SortField
+}
+
+var windowPattern = regexp.MustCompile(`modified:>="([^"]+)"`)
+
+func (s *modifiedStore) handler(t *testing.T, afterPage func(s *modifiedStore, call int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Filter    xsoar.IncidentFilter `json:"filter"`
+			FromIndex int                  `json:"fromIndex"`
+			Size      int                  `json:"size"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&reqBody)
+		assert.NoError(t, err)
+
+		var since time.Time
+		if m := windowPattern.FindStringSubmatch(reqBody.Filter.Query); m != nil {
+			since, err = time.Parse(time.RFC3339Nano, m[1])
+			assert.NoError(t, err)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.sorts = append(s.sorts, reqBody.Filter.Sort)
+
+		var matched []*xsoar.Incident
+		for _, incident := range s.incidents {
+			if !incident.Modified.Before(since) {
+				matched = append(matched, incident)
+			}
+		}
+		slices.SortFunc(matched, func(a, b *xsoar.Incident) int {
+			if c := a.Modified.Compare(b.Modified); c != 0 {
+				return c
+			}
+			return strings.Compare(a.ID, b.ID)
+		})
+		start := min(reqBody.FromIndex, len(matched))
+		end := min(start+reqBody.Size, len(matched))
+
+		err = json.NewEncoder(w).Encode(xsoar.IncidentPage{Data: matched[start:end], Total: len(matched)})
+		assert.NoError(t, err)
+
+		if afterPage != nil {
+			afterPage(s, len(s.sorts))
+		}
+	}
+}
+
+func TestIncidentService_WatchModifiedDuringPoll(t *testing.T) {
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	store := &modifiedStore{}
+	for i := range 150 {
+		store.incidents = append(store.incidents, &xsoar.Incident{
+			ID:       fmt.Sprintf("inc-%03d", i),
+			Created:  base.Add(-time.Hour),
+			Modified: base.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	client := setupTestServer(t, store.handler(t, func(s *modifiedStore, call int) {
+		if call == 1 {
+			// An incident from the first page is modified again before the
+			// second page is fetched, moving it to the end of the order.
+			s.incidents[5] = &xsoar.Incident{
+				ID:       "inc-005",
+				Created:  base.Add(-time.Hour),
+				Modified: base.Add(time.Hour),
+			}
+		}
+	}))
+
+	checkpoints := &memoryCheckpoints{checkpoint: &xsoar.WatchCheckpoint{Watermark: base}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	deliveries := make(map[string]int)
+	for event, err := range client.Incidents.Watch(ctx, nil, 10*time.Millisecond,
+		xsoar.WithCheckpoint(checkpoints),
+		xsoar.WithPageSize(100),
+		xsoar.WithConsistentIteration(),
+	) {
+		require.NoError(t, err)
+		deliveries[event.Incident.ID]++
+		if len(deliveries) == 150 && deliveries["inc-005"] == 2 {
+			break
+		}
+	}
+
+	assert.Len(t, deliveries, 150, "no incident is skipped when the order shifts")
+	assert.Equal(t, 1, deliveries["inc-101"])
+	assert.Equal(t, 2, deliveries["inc-005"])
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for _, sort := range store.sorts {
+		assert.Equal(t, []xsoar.SortField{{Field: "modified", Asc: true}, {Field: "id", Asc: true}}, sort)
+	}
+}
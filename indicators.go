@@ -0,0 +1,245 @@
+package xsoar
+
+import (
+	"context"
+	"iter"
+	"net/http"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// IndicatorService provides operations on XSOAR threat intel indicators.
+//
+//go:generate mockery --name=IndicatorService --output=mocks --outpkg=mocks --filename=indicator_service.go
+type IndicatorService interface {
+	// Search returns an iterator over all indicators matching the filter.
+	// The iterator fetches pages lazily as you iterate.
+	Search(ctx context.Context, filter *IndicatorFilter, opts ...RequestOption) iter.Seq2[*Indicator, error]
+
+	// SearchPage returns a single page of indicators.
+	// The indicators API paginates by page number, so Offset should be a
+	// multiple of Limit.
+	SearchPage(ctx context.Context, filter *IndicatorFilter, page *PageOptions, opts ...RequestOption) (*IndicatorPage, error)
+
+	// Create creates a new indicator.
+	Create(ctx context.Context, indicator *Indicator, opts ...RequestOption) (*Indicator, error)
+
+	// Edit updates an existing indicator. The indicator must have its ID
+	// and Version set, as returned by Search or Create.
+	Edit(ctx context.Context, indicator *Indicator, opts ...RequestOption) (*Indicator, error)
+
+	// Delete removes indicators by ID without adding them to the exclusion list.
+	Delete(ctx context.Context, ids []string, opts ...RequestOption) error
+
+	// Exclude adds a value to the indicator exclusion list,
+	// preventing it from being created as an indicator.
+	Exclude(ctx context.Context, req *ExcludeIndicatorRequest, opts ...RequestOption) error
+
+	// BulkCreate creates or updates indicators from a sequence. Input is
+	// uploaded in batches with bounded concurrency. A batch the server
+	// rejects as invalid is retried item by item, so one bad value only
+	// rejects itself. The report has one result per input indicator.
+	//
+	// Errors other than validation failures (authentication, server errors
+	// after retries, cancellation) stop the run; the partial report is
+	// returned together with the error. Indicators that were read but not
+	// stored are reported as rejected with the error as the reason; the
+	// rest of the sequence is not read and has no results.
+	BulkCreate(ctx context.Context, indicators iter.Seq[*Indicator], options *BulkCreateOptions, opts ...RequestOption) (*BulkCreateReport, error)
+}
+
+// indicatorService implements IndicatorService.
+type indicatorService struct {
+	transport *api.Transport
+}
+
+func newIndicatorService(transport *api.Transport) *indicatorService {
+	return &indicatorService{transport: transport}
+}
+
+// Search returns an iterator over all indicators matching the filter.
+func (s *indicatorService) Search(ctx context.Context, filter *IndicatorFilter, opts ...RequestOption) iter.Seq2[*Indicator, error] {
+	return func(yield func(*Indicator, error) bool) {
+		reqCfg := newRequestConfig()
+		reqCfg.apply(opts...)
+		offset := 0
+
+		for {
+			page, err := s.SearchPage(ctx, filter, &PageOptions{
+				Offset: offset,
+				Limit:  reqCfg.pageLimit(),
+			}, opts...)
+
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !yieldItems(ctx, page.Data, yield) {
+				return
+			}
+
+			if !page.HasMore() {
+				return
+			}
+
+			offset = page.NextOffset()
+		}
+	}
+}
+
+// SearchPage returns a single page of indicators.
+func (s *indicatorService) SearchPage(ctx context.Context, filter *IndicatorFilter, page *PageOptions, opts ...RequestOption) (*IndicatorPage, error) {
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	if page == nil {
+		page = &PageOptions{}
+	}
+	if page.Limit <= 0 {
+		page.Limit = defaultPageSize
+	}
+	if page.Limit > maxPageSize {
+		page.Limit = maxPageSize
+	}
+
+	body := &indicatorSearchRequest{
+		IndicatorFilter: filter,
+		Page:            page.Offset / page.Limit,
+		Size:            page.Limit,
+	}
+
+	var result IndicatorPage
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/indicators/search",
+		Body:       body,
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Offset = body.Page * body.Size
+	return &result, nil
+}
+
+// validateIndicator checks that an indicator has the fields required to create it.
+func validateIndicator(indicator *Indicator) error {
+	if indicator == nil {
+		return &ValidationError{
+			APIError: APIError{Message: "indicator cannot be nil"},
+		}
+	}
+	if err := validateRequired("indicator value", indicator.Value); err != nil {
+		return err
+	}
+	return validateRequired("indicator type", indicator.IndicatorType)
+}
+
+// Create creates a new indicator.
+func (s *indicatorService) Create(ctx context.Context, indicator *Indicator, opts ...RequestOption) (*Indicator, error) {
+	if err := validateIndicator(indicator); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result Indicator
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method: http.MethodPost,
+		Path:   "/indicator/create",
+		Body: map[string]any{
+			"indicator": indicator,
+			"manually":  true,
+		},
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Edit updates an existing indicator.
+func (s *indicatorService) Edit(ctx context.Context, indicator *Indicator, opts ...RequestOption) (*Indicator, error) {
+	if err := validateIndicator(indicator); err != nil {
+		return nil, err
+	}
+	if err := validateRequired("indicator ID", indicator.ID); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result Indicator
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/indicator/edit",
+		Body:       indicator,
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, &result)
+	if err != nil {
+		return nil, withResource(err, "indicator", indicator.ID)
+	}
+
+	return &result, nil
+}
+
+// Delete removes indicators by ID.
+func (s *indicatorService) Delete(ctx context.Context, ids []string, opts ...RequestOption) error {
+	if len(ids) == 0 {
+		return &ValidationError{
+			APIError: APIError{Message: "indicator IDs cannot be empty"},
+		}
+	}
+	for _, id := range ids {
+		if err := validateRequired("indicator ID", id); err != nil {
+			return err
+		}
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	// Deleting a fixed set of IDs has no additional effect when repeated.
+	return doRequest(ctx, s.transport, &api.Request{
+		Method: http.MethodPost,
+		Path:   "/indicators/batchDelete",
+		Body: map[string]any{
+			"ids":            ids,
+			"doNotWhitelist": true,
+		},
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, nil)
+}
+
+// Exclude adds a value to the indicator exclusion list.
+func (s *indicatorService) Exclude(ctx context.Context, req *ExcludeIndicatorRequest, opts ...RequestOption) error {
+	if req == nil {
+		return &ValidationError{
+			APIError: APIError{Message: "exclude request cannot be nil"},
+		}
+	}
+	if err := validateRequired("indicator value", req.Value); err != nil {
+		return err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	return doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/indicator/whitelist",
+		Body:       req,
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, nil)
+}
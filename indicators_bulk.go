@@ -0,0 +1,303 @@
+package xsoar
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// Default bulk ingestion settings.
+const (
+	defaultBulkBatchSize   = 500
+	defaultBulkConcurrency = 4
+)
+
+// indicatorBatch is a chunk of the input sequence with the input positions of its items.
+type indicatorBatch struct {
+	indexes    []int
+	indicators []*Indicator
+}
+
+// BulkCreate creates or updates indicators from a sequence in batches.
+func (s *indicatorService) BulkCreate(ctx context.Context, indicators iter.Seq[*Indicator], options *BulkCreateOptions, opts ...RequestOption) (*BulkCreateReport, error) {
+	batchSize, concurrency := defaultBulkBatchSize, defaultBulkConcurrency
+	if options != nil && options.BatchSize > 0 {
+		batchSize = min(options.BatchSize, maxPageSize)
+	}
+	if options != nil && options.Concurrency > 0 {
+		concurrency = options.Concurrency
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var (
+		mu      sync.Mutex
+		results []BulkItemResult
+	)
+	record := func(items ...BulkItemResult) {
+		mu.Lock()
+		results = append(results, items...)
+		mu.Unlock()
+	}
+
+	batches := make(chan indicatorBatch)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Go(func() {
+			for batch := range batches {
+				items, err := s.createBatch(ctx, batch, opts)
+				record(items...)
+				if err != nil {
+					cancel(err)
+					record(abortedResults(batch, len(items), context.Cause(ctx))...)
+				}
+			}
+		})
+	}
+
+	s.produceBatches(ctx, indicators, batchSize, batches, record)
+	close(batches)
+	wg.Wait()
+
+	report := newBulkCreateReport(results)
+	if err := context.Cause(ctx); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// produceBatches chunks the input sequence into batches. Indicators that fail
+// local validation are rejected immediately and never sent. Once the run is
+// aborted, the pending batch is rejected and the rest of the sequence is not
+// read.
+func (s *indicatorService) produceBatches(ctx context.Context, indicators iter.Seq[*Indicator], batchSize int, batches chan<- indicatorBatch, record func(...BulkItemResult)) {
+	batch := indicatorBatch{}
+	send := func() bool {
+		if len(batch.indicators) == 0 {
+			return true
+		}
+		select {
+		case batches <- batch:
+			batch = indicatorBatch{}
+			return true
+		case <-ctx.Done():
+			record(abortedResults(batch, 0, context.Cause(ctx))...)
+			return false
+		}
+	}
+
+	index := 0
+	for indicator := range indicators {
+		if err := validateIndicator(indicator); err != nil {
+			record(rejectedResult(index, indicator, err))
+		} else {
+			batch.indexes = append(batch.indexes, index)
+			batch.indicators = append(batch.indicators, indicator)
+		}
+		index++
+
+		if len(batch.indicators) >= batchSize && !send() {
+			return
+		}
+	}
+	send()
+}
+
+// createBatch uploads a batch. If the server rejects the batch as invalid,
+// the items are retried one by one so that a single bad value only rejects itself.
+// The returned error is fatal and aborts the whole run; the results returned
+// with it cover a prefix of the batch.
+func (s *indicatorService) createBatch(ctx context.Context, batch indicatorBatch, opts []RequestOption) ([]BulkItemResult, error) {
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var stored []*Indicator
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method: http.MethodPost,
+		Path:   "/indicators/batch",
+		Body: map[string]any{
+			"indicators": batch.indicators,
+			"manually":   true,
+		},
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, &stored)
+
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return s.createEach(ctx, batch, opts)
+	case err != nil:
+		return nil, err
+	}
+
+	matched := matchStored(batch.indicators, stored)
+	items := make([]BulkItemResult, 0, len(batch.indicators))
+	for i, indicator := range batch.indicators {
+		if matched[i] == nil {
+			items = append(items, BulkItemResult{
+				Index:  batch.indexes[i],
+				Value:  indicator.Value,
+				Status: BulkItemRejected,
+				Reason: "not returned by server",
+			})
+			continue
+		}
+		items = append(items, storedResult(batch.indexes[i], indicator, matched[i]))
+	}
+	return items, nil
+}
+
+// matchStored pairs each input indicator with the indicator the server
+// stored for it, leaving nil for inputs missing from the response. Values
+// may come back normalized (case, trailing dot, type alias) and in any
+// order, so results are matched on the normalized key, then on the value
+// alone. The position in the response only breaks ties between equal keys,
+// and pairs the leftovers when the counts agree.
+func matchStored(inputs, stored []*Indicator) []*Indicator {
+	matched := make([]*Indicator, len(inputs))
+	used := make([]bool, len(stored))
+
+	byKey := make(map[indicatorKey][]int, len(stored))
+	byValue := make(map[string][]int, len(stored))
+	for j, indicator := range stored {
+		key := keyOf(indicator)
+		byKey[key] = append(byKey[key], j)
+		byValue[key.value] = append(byValue[key.value], j)
+	}
+
+	// claim matches input i with an unused candidate, preferring the one
+	// at the same position.
+	claim := func(i int, candidates []int) {
+		pick := -1
+		for _, j := range candidates {
+			if !used[j] && (pick < 0 || j == i) {
+				pick = j
+			}
+		}
+		if pick >= 0 {
+			used[pick] = true
+			matched[i] = stored[pick]
+		}
+	}
+
+	for i, input := range inputs {
+		claim(i, byKey[keyOf(input)])
+	}
+	for i, input := range inputs {
+		if matched[i] == nil {
+			claim(i, byValue[keyOf(input).value])
+		}
+	}
+	if len(stored) == len(inputs) {
+		for i := range inputs {
+			if matched[i] == nil && !used[i] {
+				used[i] = true
+				matched[i] = stored[i]
+			}
+		}
+	}
+	return matched
+}
+
+// createEach creates the indicators of a batch individually.
+func (s *indicatorService) createEach(ctx context.Context, batch indicatorBatch, opts []RequestOption) ([]BulkItemResult, error) {
+	items := make([]BulkItemResult, 0, len(batch.indicators))
+	for i, indicator := range batch.indicators {
+		result, err := s.Create(ctx, indicator, opts...)
+
+		var validationErr *ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			items = append(items, rejectedResult(batch.indexes[i], indicator, err))
+		case err != nil:
+			return items, err
+		default:
+			items = append(items, storedResult(batch.indexes[i], indicator, result))
+		}
+	}
+	return items, nil
+}
+
+// indicatorKey identifies an indicator within a batch.
+type indicatorKey struct {
+	value         string
+	indicatorType string
+}
+
+// keyOf returns the normalized key of an indicator, ignoring the case,
+// surrounding whitespace and trailing dot the server may normalize away.
+func keyOf(indicator *Indicator) indicatorKey {
+	value := strings.TrimSuffix(strings.TrimSpace(indicator.Value), ".")
+	return indicatorKey{
+		value:         strings.ToLower(value),
+		indicatorType: strings.ToLower(indicator.IndicatorType),
+	}
+}
+
+// storedResult builds the result for an indicator accepted by the server. A stored
+// version above 1 means an existing indicator was merged rather than created.
+func storedResult(index int, input, result *Indicator) BulkItemResult {
+	status := BulkItemCreated
+	if result.Version > 1 {
+		status = BulkItemUpdated
+	}
+	return BulkItemResult{
+		Index:     index,
+		Value:     input.Value,
+		Status:    status,
+		Indicator: result,
+	}
+}
+
+// rejectedResult builds the result for an indicator that was not stored.
+func rejectedResult(index int, input *Indicator, err error) BulkItemResult {
+	result := BulkItemResult{
+		Index:  index,
+		Status: BulkItemRejected,
+		Reason: err.Error(),
+	}
+	if input != nil {
+		result.Value = input.Value
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) && validationErr.Message != "" {
+		result.Reason = validationErr.Message
+	}
+	return result
+}
+
+// abortedResults rejects the indicators of a batch from position from on,
+// which were not stored because the run was aborted with err.
+func abortedResults(batch indicatorBatch, from int, err error) []BulkItemResult {
+	items := make([]BulkItemResult, 0, len(batch.indicators)-from)
+	for i := from; i < len(batch.indicators); i++ {
+		items = append(items, rejectedResult(batch.indexes[i], batch.indicators[i], err))
+	}
+	return items
+}
+
+// newBulkCreateReport orders results by input position and tallies outcomes.
+func newBulkCreateReport(results []BulkItemResult) *BulkCreateReport {
+	slices.SortFunc(results, func(a, b BulkItemResult) int { return a.Index - b.Index })
+
+	report := &BulkCreateReport{Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case BulkItemCreated:
+			report.Created++
+		case BulkItemUpdated:
+			report.Updated++
+		case BulkItemRejected:
+			report.Rejected++
+		}
+	}
+	return report
+}
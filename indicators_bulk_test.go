@@ -0,0 +1,270 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+// bulkHandler echoes submitted indicators. Values starting with "bad" make
+// the batch fail validation and are rejected by the single-create endpoint;
+// values starting with "old" are returned as already existing.
+func bulkHandler(t *testing.T, batchCalls, createCalls *atomic.Int32) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/indicators/batch":
+			batchCalls.Add(1)
+			var reqBody struct {
+				Indicators []*xsoar.Indicator `json:"indicators"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+
+			for _, indicator := range reqBody.Indicators {
+				if strings.HasPrefix(indicator.Value, "bad") {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				indicator.Version = 1
+				if strings.HasPrefix(indicator.Value, "old") {
+					indicator.Version = 4
+				}
+			}
+			err = json.NewEncoder(w).Encode(reqBody.Indicators)
+			assert.NoError(t, err)
+
+		case "/indicator/create":
+			createCalls.Add(1)
+			var reqBody struct {
+				Indicator xsoar.Indicator `json:"indicator"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+
+			if strings.HasPrefix(reqBody.Indicator.Value, "bad") {
+				w.WriteHeader(http.StatusBadRequest)
+				err = json.NewEncoder(w).Encode(map[string]any{"message": "invalid IP value"})
+				assert.NoError(t, err)
+				return
+			}
+			reqBody.Indicator.Version = 1
+			err = json.NewEncoder(w).Encode(reqBody.Indicator)
+			assert.NoError(t, err)
+
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}
+}
+
+func ipIndicators(values ...string) iter.Seq[*xsoar.Indicator] {
+	return func(yield func(*xsoar.Indicator) bool) {
+		for _, v := range values {
+			if !yield(&xsoar.Indicator{Value: v, IndicatorType: "IP"}) {
+				return
+			}
+		}
+	}
+}
+
+func TestIndicatorService_BulkCreate(t *testing.T) {
+	t.Run("reports per-item outcomes", func(t *testing.T) {
+		var batchCalls, createCalls atomic.Int32
+		client := setupTestServer(t, bulkHandler(t, &batchCalls, &createCalls))
+
+		report, err := client.Indicators.BulkCreate(context.Background(),
+			ipIndicators("10.0.0.1", "old-10.0.0.2", "bad-value", "10.0.0.4", "10.0.0.5"),
+			&xsoar.BulkCreateOptions{BatchSize: 2, Concurrency: 2},
+		)
+		require.NoError(t, err)
+
+		require.Len(t, report.Results, 5)
+		statuses := make([]xsoar.BulkItemStatus, 0, len(report.Results))
+		for i, result := range report.Results {
+			assert.Equal(t, i, result.Index)
+			statuses = append(statuses, result.Status)
+		}
+		assert.Equal(t, []xsoar.BulkItemStatus{
+			xsoar.BulkItemCreated,
+			xsoar.BulkItemUpdated,
+			xsoar.BulkItemRejected,
+			xsoar.BulkItemCreated,
+			xsoar.BulkItemCreated,
+		}, statuses)
+		assert.Equal(t, "invalid IP value", report.Results[2].Reason)
+		assert.Equal(t, 3, report.Created)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 1, report.Rejected)
+
+		assert.Equal(t, int32(3), batchCalls.Load())
+		assert.Equal(t, int32(2), createCalls.Load(), "only the failed batch is retried item by item")
+	})
+
+	t.Run("rejects invalid input without sending it", func(t *testing.T) {
+		var batchCalls, createCalls atomic.Int32
+		client := setupTestServer(t, bulkHandler(t, &batchCalls, &createCalls))
+
+		input := slices.Values([]*xsoar.Indicator{
+			{Value: "10.0.0.1", IndicatorType: "IP"},
+			{Value: "no-type"},
+			nil,
+		})
+		report, err := client.Indicators.BulkCreate(context.Background(), input, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 2, report.Rejected)
+		assert.Equal(t, "indicator type cannot be empty", report.Results[1].Reason)
+		assert.Equal(t, int32(1), batchCalls.Load())
+	})
+
+	t.Run("matches normalized values", func(t *testing.T) {
+		tests := []struct {
+			name string
+			drop string // value the server leaves out of its response
+		}{
+			{name: "all returned"},
+			{name: "one dropped", drop: "Evil.Example.COM."},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+					var reqBody struct {
+						Indicators []*xsoar.Indicator `json:"indicators"`
+					}
+					err := json.NewDecoder(r.Body).Decode(&reqBody)
+					assert.NoError(t, err)
+
+					stored := make([]*xsoar.Indicator, 0, len(reqBody.Indicators))
+					for _, indicator := range reqBody.Indicators {
+						if indicator.Value == tt.drop {
+							continue
+						}
+						stored = append(stored, &xsoar.Indicator{
+							Value:         strings.TrimSuffix(strings.ToLower(indicator.Value), "."),
+							IndicatorType: indicator.IndicatorType,
+							Version:       1,
+						})
+					}
+					err = json.NewEncoder(w).Encode(stored)
+					assert.NoError(t, err)
+				})
+
+				input := slices.Values([]*xsoar.Indicator{
+					{Value: "Phish.Example.COM.", IndicatorType: "Domain"},
+					{Value: "HTTPS://Example.com/Login", IndicatorType: "URL"},
+					{Value: "Evil.Example.COM.", IndicatorType: "Domain"},
+				})
+				report, err := client.Indicators.BulkCreate(context.Background(), input, nil)
+				require.NoError(t, err)
+
+				require.Len(t, report.Results, 3)
+				assert.Equal(t, xsoar.BulkItemCreated, report.Results[0].Status)
+				assert.Equal(t, "phish.example.com", report.Results[0].Indicator.Value)
+				assert.Equal(t, xsoar.BulkItemCreated, report.Results[1].Status)
+				if tt.drop == "" {
+					assert.Equal(t, 3, report.Created)
+				} else {
+					assert.Equal(t, xsoar.BulkItemRejected, report.Results[2].Status)
+					assert.Equal(t, 2, report.Created)
+				}
+			})
+		}
+	})
+
+	t.Run("matches results the server reorders", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var reqBody struct {
+				Indicators []*xsoar.Indicator `json:"indicators"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+
+			stored := make([]*xsoar.Indicator, 0, len(reqBody.Indicators))
+			for _, indicator := range reqBody.Indicators {
+				version := 1
+				if strings.HasPrefix(indicator.Value, "old") {
+					version = 4
+				}
+				stored = append(stored, &xsoar.Indicator{
+					Value:         strings.ToLower(indicator.Value),
+					IndicatorType: indicator.IndicatorType,
+					Version:       version,
+				})
+			}
+			slices.Reverse(stored)
+			err = json.NewEncoder(w).Encode(stored)
+			assert.NoError(t, err)
+		})
+
+		input := slices.Values([]*xsoar.Indicator{
+			{Value: "New.Example.com", IndicatorType: "Domain"},
+			{Value: "old.example.com", IndicatorType: "Domain"},
+			{Value: "New.Example.com", IndicatorType: "Domain"},
+			{Value: "Other.Example.com", IndicatorType: "Domain"},
+		})
+		report, err := client.Indicators.BulkCreate(context.Background(), input, nil)
+		require.NoError(t, err)
+
+		require.Len(t, report.Results, 4)
+		for _, result := range report.Results {
+			require.NotNil(t, result.Indicator)
+			assert.Equal(t, strings.ToLower(result.Value), result.Indicator.Value)
+		}
+		assert.Equal(t, xsoar.BulkItemUpdated, report.Results[1].Status)
+		assert.Equal(t, 3, report.Created)
+		assert.Equal(t, 1, report.Updated)
+	})
+
+	t.Run("stops on fatal error with partial report", func(t *testing.T) {
+		var calls atomic.Int32
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) > 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			var reqBody struct {
+				Indicators []*xsoar.Indicator `json:"indicators"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			err = json.NewEncoder(w).Encode(reqBody.Indicators)
+			assert.NoError(t, err)
+		})
+
+		values := make([]string, 10)
+		for i := range values {
+			values[i] = fmt.Sprintf("10.0.0.%d", i)
+		}
+		report, err := client.Indicators.BulkCreate(context.Background(), ipIndicators(values...),
+			&xsoar.BulkCreateOptions{BatchSize: 2, Concurrency: 1},
+		)
+		require.Error(t, err)
+
+		var authErr *xsoar.AuthenticationError
+		require.ErrorAs(t, err, &authErr)
+		require.NotNil(t, report)
+		assert.Equal(t, 2, report.Created)
+
+		// Every indicator read before the run stopped has a result; the
+		// failed batch is rejected with the fatal error.
+		require.GreaterOrEqual(t, len(report.Results), 4)
+		for i, result := range report.Results {
+			assert.Equal(t, i, result.Index)
+		}
+		assert.Equal(t, len(report.Results)-2, report.Rejected)
+		assert.Equal(t, xsoar.BulkItemRejected, report.Results[2].Status)
+		assert.Equal(t, authErr.Error(), report.Results[2].Reason)
+	})
+}
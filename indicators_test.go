@@ -0,0 +1,216 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+func TestIndicatorService_SearchPage(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/indicators/search", r.URL.Path)
+
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, "type:IP", reqBody["query"])
+			assert.InDelta(t, float64(2), reqBody["page"], 0.001)
+			assert.InDelta(t, float64(50), reqBody["size"], 0.001)
+
+			_, err = w.Write([]byte(`{
+				"iocObjects": [{
+					"id": "ioc-1",
+					"value": "1.1.1.1",
+					"indicator_type": "IP",
+					"score": 3,
+					"sourceBrands": ["VirusTotal"],
+					"comments": [{"content": "seen in phishing"}],
+					"CustomFields": {"tags": ["c2"]}
+				}],
+				"total": 101
+			}`))
+			assert.NoError(t, err)
+		})
+
+		page, err := client.Indicators.SearchPage(context.Background(), &xsoar.IndicatorFilter{
+			Query: "type:IP",
+		}, &xsoar.PageOptions{Offset: 100, Limit: 50})
+		require.NoError(t, err)
+
+		require.Len(t, page.Data, 1)
+		indicator := page.Data[0]
+		assert.Equal(t, "1.1.1.1", indicator.Value)
+		assert.Equal(t, "IP", indicator.IndicatorType)
+		assert.Equal(t, xsoar.ScoreBad, indicator.Score)
+		assert.Equal(t, []string{"VirusTotal"}, indicator.Sources)
+		assert.Equal(t, "seen in phishing", indicator.Comments[0].Content)
+		assert.Equal(t, 100, page.Offset)
+		assert.False(t, page.HasMore())
+	})
+}
+
+func TestIndicatorService_Search(t *testing.T) {
+	t.Run("iterates all pages", func(t *testing.T) {
+		callCount := 0
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			callCount++
+
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+
+			data := make([]*xsoar.Indicator, 100)
+			for i := range data {
+				data[i] = &xsoar.Indicator{Value: "v"}
+			}
+			if reqBody["page"] == float64(1) {
+				data = data[:20]
+			}
+
+			err = json.NewEncoder(w).Encode(map[string]any{"iocObjects": data, "total": 120})
+			assert.NoError(t, err)
+		})
+
+		indicators, err := xsoar.Collect(client.Indicators.Search(context.Background(), nil))
+		require.NoError(t, err)
+
+		assert.Len(t, indicators, 120)
+		assert.Equal(t, 2, callCount)
+	})
+}
+
+func TestIndicatorService_Create(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/indicator/create", r.URL.Path)
+
+			var reqBody struct {
+				Indicator xsoar.Indicator `json:"indicator"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, "evil.example.com", reqBody.Indicator.Value)
+			assert.Equal(t, "Domain", reqBody.Indicator.IndicatorType)
+
+			reqBody.Indicator.ID = "ioc-1"
+			reqBody.Indicator.Version = 1
+			err = json.NewEncoder(w).Encode(reqBody.Indicator)
+			assert.NoError(t, err)
+		})
+
+		indicator, err := client.Indicators.Create(context.Background(), &xsoar.Indicator{
+			Value:         "evil.example.com",
+			IndicatorType: "Domain",
+			Score:         xsoar.ScoreBad,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "ioc-1", indicator.ID)
+	})
+
+	t.Run("missing type returns validation error", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call with invalid indicator")
+		})
+
+		_, err := client.Indicators.Create(context.Background(), &xsoar.Indicator{Value: "1.1.1.1"})
+
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestIndicatorService_Edit(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/indicator/edit", r.URL.Path)
+
+			var reqBody xsoar.Indicator
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, "ioc-1", reqBody.ID)
+			assert.Equal(t, 2, reqBody.Version)
+
+			reqBody.Version++
+			err = json.NewEncoder(w).Encode(reqBody)
+			assert.NoError(t, err)
+		})
+
+		indicator, err := client.Indicators.Edit(context.Background(), &xsoar.Indicator{
+			ID:            "ioc-1",
+			Value:         "1.1.1.1",
+			IndicatorType: "IP",
+			Version:       2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, indicator.Version)
+	})
+
+	t.Run("missing ID returns validation error", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call without ID")
+		})
+
+		_, err := client.Indicators.Edit(context.Background(), &xsoar.Indicator{
+			Value:         "1.1.1.1",
+			IndicatorType: "IP",
+		})
+
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestIndicatorService_Delete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/indicators/batchDelete", r.URL.Path)
+
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, []any{"ioc-1", "ioc-2"}, reqBody["ids"])
+			assert.Equal(t, true, reqBody["doNotWhitelist"])
+		})
+
+		err := client.Indicators.Delete(context.Background(), []string{"ioc-1", "ioc-2"})
+		require.NoError(t, err)
+	})
+
+	t.Run("empty IDs returns validation error", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call without IDs")
+		})
+
+		err := client.Indicators.Delete(context.Background(), nil)
+
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestIndicatorService_Exclude(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/indicator/whitelist", r.URL.Path)
+
+		var reqBody xsoar.ExcludeIndicatorRequest
+		err := json.NewDecoder(r.Body).Decode(&reqBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "8.8.8.8", reqBody.Value)
+		assert.Equal(t, "IP", reqBody.IndicatorType)
+		assert.Equal(t, "public DNS", reqBody.Reason)
+	})
+
+	err := client.Indicators.Exclude(context.Background(), &xsoar.ExcludeIndicatorRequest{
+		Value:         "8.8.8.8",
+		IndicatorType: "IP",
+		Reason:        "public DNS",
+	})
+	require.NoError(t, err)
+}
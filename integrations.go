@@ -0,0 +1,284 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// IntegrationService provides operations on integrations and their
+// configured instances.
+//
+//go:generate mockery --name=IntegrationService --output=mocks --outpkg=mocks --filename=integration_service.go
+type IntegrationService interface {
+	// Brands returns the installed integrations that instances can be
+	// created from, with their parameter definitions.
+	Brands(ctx context.Context, opts ...RequestOption) ([]*IntegrationBrand, error)
+
+	// Instances returns all configured integration instances.
+	Instances(ctx context.Context, opts ...RequestOption) ([]*IntegrationInstance, error)
+
+	// GetInstance retrieves a configured instance by name.
+	GetInstance(ctx context.Context, name string, opts ...RequestOption) (*IntegrationInstance, error)
+
+	// CreateInstance creates an instance. Use IntegrationBrand.NewInstance
+	// to start from the brand's parameter definitions.
+	CreateInstance(ctx context.Context, instance *IntegrationInstance, opts ...RequestOption) (*IntegrationInstance, error)
+
+	// UpdateInstance saves changes to an existing instance, such as
+	// rotated credentials set with SetParam.
+	UpdateInstance(ctx context.Context, instance *IntegrationInstance, opts ...RequestOption) (*IntegrationInstance, error)
+
+	// DeleteInstance removes an instance by ID.
+	DeleteInstance(ctx context.Context, id string, opts ...RequestOption) error
+
+	// EnableInstance enables an instance by name.
+	EnableInstance(ctx context.Context, name string, opts ...RequestOption) error
+
+	// DisableInstance disables an instance by name.
+	DisableInstance(ctx context.Context, name string, opts ...RequestOption) error
+
+	// TestInstance runs the integration's test module with the instance's
+	// configuration. The instance does not need to be saved, so new
+	// credentials can be tested before they are applied. A failing test is
+	// reported in the result, not as an error.
+	TestInstance(ctx context.Context, instance *IntegrationInstance, opts ...RequestOption) (*IntegrationTestResult, error)
+}
+
+// integrationService implements IntegrationService.
+type integrationService struct {
+	transport *api.Transport
+}
+
+func newIntegrationService(transport *api.Transport) *integrationService {
+	return &integrationService{transport: transport}
+}
+
+// Brands returns the installed integrations.
+func (s *integrationService) Brands(ctx context.Context, opts ...RequestOption) ([]*IntegrationBrand, error) {
+	var brands []*IntegrationBrand
+	err := s.search(ctx, opts, func(result *integrationSearchResponse) int {
+		brands = append(brands, result.Brands...)
+		return len(result.Brands)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return brands, nil
+}
+
+// Instances returns all configured integration instances. Each instance
+// carries its brand definition in Configuration, as saving requires it.
+func (s *integrationService) Instances(ctx context.Context, opts ...RequestOption) ([]*IntegrationInstance, error) {
+	var instances []*IntegrationInstance
+	brands := make(map[string]*IntegrationBrand)
+	err := s.search(ctx, opts, func(result *integrationSearchResponse) int {
+		instances = append(instances, result.Instances...)
+		for _, brand := range result.Brands {
+			brands[brand.Name] = brand
+		}
+		// Brands and instances are paged together; keep paging until
+		// both are exhausted.
+		return max(len(result.Instances), len(result.Brands))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, instance := range instances {
+		if instance.Configuration == nil {
+			instance.Configuration = brands[instance.Brand]
+		}
+	}
+	return instances, nil
+}
+
+// search pages through /settings/integration/search. collect consumes a
+// page and returns the number of items it used; paging stops at a short page.
+func (s *integrationService) search(ctx context.Context, opts []RequestOption, collect func(*integrationSearchResponse) int) error {
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	body := &integrationSearchRequest{Size: reqCfg.pageLimit()}
+	for {
+		var result integrationSearchResponse
+		err := doRequest(ctx, s.transport, &api.Request{
+			Method:     http.MethodPost,
+			Path:       "/settings/integration/search",
+			Body:       body,
+			Headers:    reqCfg.headers,
+			Idempotent: true,
+		}, &result)
+		if err != nil {
+			return err
+		}
+
+		if collect(&result) < body.Size {
+			return nil
+		}
+		body.Page++
+	}
+}
+
+// GetInstance retrieves a configured instance by name.
+func (s *integrationService) GetInstance(ctx context.Context, name string, opts ...RequestOption) (*IntegrationInstance, error) {
+	if err := validateRequired("instance name", name); err != nil {
+		return nil, err
+	}
+
+	instances, err := s.Instances(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for _, instance := range instances {
+		if instance.Name == name {
+			return instance, nil
+		}
+	}
+
+	return nil, &NotFoundError{
+		APIError:     APIError{StatusCode: http.StatusNotFound, Message: "integration instance not found"},
+		ResourceType: "integration instance",
+		ResourceID:   name,
+	}
+}
+
+// CreateInstance creates an instance.
+func (s *integrationService) CreateInstance(ctx context.Context, instance *IntegrationInstance, opts ...RequestOption) (*IntegrationInstance, error) {
+	if err := validateInstance(instance); err != nil {
+		return nil, err
+	}
+	if instance.ID != "" {
+		return nil, &ValidationError{APIError: APIError{Message: "instance ID must be empty on create"}}
+	}
+	return s.save(ctx, instance, opts)
+}
+
+// UpdateInstance saves changes to an existing instance.
+func (s *integrationService) UpdateInstance(ctx context.Context, instance *IntegrationInstance, opts ...RequestOption) (*IntegrationInstance, error) {
+	if err := validateInstance(instance); err != nil {
+		return nil, err
+	}
+	if err := validateRequired("instance ID", instance.ID); err != nil {
+		return nil, err
+	}
+	return s.save(ctx, instance, opts)
+}
+
+// save creates or updates an instance.
+func (s *integrationService) save(ctx context.Context, instance *IntegrationInstance, opts []RequestOption) (*IntegrationInstance, error) {
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result IntegrationInstance
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPut,
+		Path:       "/settings/integration",
+		Body:       instance,
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DeleteInstance removes an instance by ID.
+func (s *integrationService) DeleteInstance(ctx context.Context, id string, opts ...RequestOption) error {
+	if err := validateRequired("instance ID", id); err != nil {
+		return err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:  http.MethodDelete,
+		Path:    fmt.Sprintf("/settings/integration/%s", url.PathEscape(id)),
+		Headers: reqCfg.headers,
+	}, nil)
+	return withResource(err, "integration instance", id)
+}
+
+// EnableInstance enables an instance by name.
+func (s *integrationService) EnableInstance(ctx context.Context, name string, opts ...RequestOption) error {
+	return s.setEnabled(ctx, name, true, opts)
+}
+
+// DisableInstance disables an instance by name.
+func (s *integrationService) DisableInstance(ctx context.Context, name string, opts ...RequestOption) error {
+	return s.setEnabled(ctx, name, false, opts)
+}
+
+// setEnabled saves an instance with its enabled flag changed. Instances
+// already in the requested state are left untouched.
+func (s *integrationService) setEnabled(ctx context.Context, name string, enabled bool, opts []RequestOption) error {
+	instance, err := s.GetInstance(ctx, name, opts...)
+	if err != nil {
+		return err
+	}
+	if instance.Enabled == enabled {
+		return nil
+	}
+
+	instance.Enabled = enabled
+	_, err = s.save(ctx, instance, opts)
+	return err
+}
+
+// TestInstance runs the integration's test module for an instance.
+func (s *integrationService) TestInstance(ctx context.Context, instance *IntegrationInstance, opts ...RequestOption) (*IntegrationTestResult, error) {
+	if err := validateInstance(instance); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result IntegrationTestResult
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/settings/integration/test",
+		Body:       instance,
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// validateInstance checks that an instance has the fields required to save
+// or test it, and that all required parameters have a value.
+func validateInstance(instance *IntegrationInstance) error {
+	if instance == nil {
+		return &ValidationError{APIError: APIError{Message: "instance cannot be nil"}}
+	}
+	if err := validateRequired("instance name", instance.Name); err != nil {
+		return err
+	}
+	if err := validateRequired("instance brand", instance.Brand); err != nil {
+		return err
+	}
+
+	missing := make(map[string]string)
+	for _, param := range instance.Data {
+		if param.Required && !param.HasValue {
+			missing[param.Name] = "required"
+		}
+	}
+	if len(missing) > 0 {
+		return &ValidationError{
+			APIError: APIError{Message: "missing required parameters"},
+			Fields:   missing,
+		}
+	}
+	return nil
+}
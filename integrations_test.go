@@ -0,0 +1,161 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+const integrationSearchResponse = `{
+	"configurations": [
+		{"id": "VirusTotal", "name": "VirusTotal", "category": "Data Enrichment",
+		 "configuration": [{"name": "apikey", "type": 4, "required": true}]}
+	],
+	"instances": [
+		{"id": "i-1", "name": "VT_prod", "brand": "VirusTotal", "enabled": "true",
+		 "data": [{"name": "apikey", "type": 4, "value": "old", "hasvalue": true, "required": true}]},
+		{"id": "i-2", "name": "VT_test", "brand": "VirusTotal", "enabled": "false", "data": []}
+	]
+}`
+
+func TestIntegrationService_Search(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/settings/integration/search", r.URL.Path)
+		_, err := w.Write([]byte(integrationSearchResponse))
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+
+	t.Run("Brands", func(t *testing.T) {
+		brands, err := client.Integrations.Brands(ctx)
+		require.NoError(t, err)
+		require.Len(t, brands, 1)
+		assert.Equal(t, xsoar.ParamTypeEncrypted, brands[0].Configuration[0].Type)
+	})
+
+	t.Run("Instances", func(t *testing.T) {
+		instances, err := client.Integrations.Instances(ctx)
+		require.NoError(t, err)
+		require.Len(t, instances, 2)
+		assert.True(t, instances[0].Enabled)
+		assert.False(t, instances[1].Enabled)
+		require.NotNil(t, instances[0].Configuration)
+		assert.Equal(t, "Data Enrichment", instances[0].Configuration.Category)
+	})
+
+	t.Run("GetInstance not found", func(t *testing.T) {
+		_, err := client.Integrations.GetInstance(ctx, "missing")
+		var notFoundErr *xsoar.NotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "missing", notFoundErr.ResourceID)
+	})
+}
+
+func TestIntegrationService_UpdateInstance(t *testing.T) {
+	t.Run("rotates credentials", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/settings/integration/search":
+				_, err := w.Write([]byte(integrationSearchResponse))
+				assert.NoError(t, err)
+			case "/settings/integration":
+				assert.Equal(t, http.MethodPut, r.Method)
+
+				var instance xsoar.IntegrationInstance
+				err := json.NewDecoder(r.Body).Decode(&instance)
+				assert.NoError(t, err)
+				assert.Equal(t, "i-1", instance.ID)
+				assert.Equal(t, "new", instance.Data[0].Value)
+				if assert.NotNil(t, instance.Configuration, "configuration must be sent") {
+					assert.Equal(t, "VirusTotal", instance.Configuration.Name)
+				}
+
+				err = json.NewEncoder(w).Encode(&instance)
+				assert.NoError(t, err)
+			}
+		})
+
+		ctx := context.Background()
+		instance, err := client.Integrations.GetInstance(ctx, "VT_prod")
+		require.NoError(t, err)
+		require.NoError(t, instance.SetParam("apikey", "new"))
+
+		updated, err := client.Integrations.UpdateInstance(ctx, instance)
+		require.NoError(t, err)
+		assert.True(t, updated.Enabled)
+	})
+
+	t.Run("rejects missing required parameters", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call for an incomplete instance")
+		})
+
+		brand := &xsoar.IntegrationBrand{
+			Name:          "VirusTotal",
+			Configuration: []xsoar.IntegrationParam{{Name: "apikey", Type: xsoar.ParamTypeEncrypted, Required: true}},
+		}
+
+		ctx := context.Background()
+		_, err := client.Integrations.CreateInstance(ctx, brand.NewInstance("VT_new"))
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, map[string]string{"apikey": "required"}, validationErr.Fields)
+	})
+}
+
+func TestIntegrationService_DisableInstance(t *testing.T) {
+	saves := 0
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/settings/integration/search":
+			_, err := w.Write([]byte(integrationSearchResponse))
+			assert.NoError(t, err)
+		case "/settings/integration":
+			saves++
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, "VT_prod", reqBody["name"])
+			assert.Equal(t, "false", reqBody["enabled"])
+			assert.Contains(t, reqBody, "configuration")
+			_, err = w.Write([]byte(`{}`))
+			assert.NoError(t, err)
+		}
+	})
+
+	ctx := context.Background()
+	require.NoError(t, client.Integrations.DisableInstance(ctx, "VT_prod"))
+	require.NoError(t, client.Integrations.DisableInstance(ctx, "VT_test"))
+	assert.Equal(t, 1, saves, "already disabled instances are not saved")
+}
+
+func TestIntegrationService_DeleteInstance(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/settings/integration/i-1", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ctx := context.Background()
+	require.NoError(t, client.Integrations.DeleteInstance(ctx, "i-1"))
+}
+
+func TestIntegrationService_TestInstance(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/settings/integration/test", r.URL.Path)
+		_, err := w.Write([]byte(`{"success": false, "message": "401 Unauthorized"}`))
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+	result, err := client.Integrations.TestInstance(ctx, &xsoar.IntegrationInstance{Name: "VT_prod", Brand: "VirusTotal"})
+	require.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "401 Unauthorized", result.Message)
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures automatic retries of failed requests.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the first attempt.
+	MaxRetries int
+
+	// InitialBackoff is the base delay before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the computed exponential delay.
+	MaxBackoff time.Duration
+}
+
+// canRetry reports whether req may be sent more than once.
+// Requests using idempotent HTTP methods are always safe; others must be
+// explicitly marked as idempotent by the caller. PUT is not treated as
+// idempotent because the API uses it to create resources. Raw bodies are
+// consumed by the first attempt and cannot be retried.
+func canRetry(req *Request) bool {
+	if req.RawBody != nil {
+		return false
+	}
+	if req.Idempotent {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay decides whether an attempt should be retried and how long to wait.
+func (p *RetryPolicy) retryDelay(attempt int, resp *Response, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxRetries {
+		return 0, false
+	}
+
+	switch {
+	case err != nil:
+		if !isTransient(err) {
+			return 0, false
+		}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if d := ParseRetryAfter(resp.Headers.Get("Retry-After")); d > 0 {
+			return d, true
+		}
+	case resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented:
+	default:
+		return 0, false
+	}
+
+	return p.backoff(attempt), true
+}
+
+// backoff returns the jittered exponential delay for the given attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.MaxBackoff
+	if attempt < 32 {
+		if exp := p.InitialBackoff << attempt; exp > 0 && exp < d {
+			d = exp
+		}
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Equal jitter: wait at least half of the computed delay.
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// isTransient reports whether a transport-level error is likely to succeed on retry.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sleep waits for d or until ctx is done. It returns false if the wait
+// was cut short or if ctx's deadline would expire before d elapses.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return false
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// ParseRetryAfter parses the Retry-After header value.
+// It handles both seconds (integer) and HTTP-date formats.
+func ParseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	// Try parsing as seconds first
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	// Try parsing as HTTP-date (RFC 1123)
+	if t, err := time.Parse(time.RFC1123, value); err == nil {
+		duration := time.Until(t)
+		if duration > 0 {
+			return duration
+		}
+	}
+
+	return 0
+}
@@ -18,21 +18,30 @@ import (
 
 const (
 	defaultHTTPTimeout = 30 * time.Second
-	defaultMaxBodySize = 10 * 1024 * 1024 // 10MB
+
+	// DefaultMaxBodySize is the default limit for buffered response bodies.
+	DefaultMaxBodySize = 10 * 1024 * 1024 // 10MB
 )
 
 // Transport handles HTTP communication with the XSOAR API.
 type Transport struct {
-	BaseURL     *url.URL
-	HTTPClient  *http.Client
-	Credentials *auth.Credentials
-	UserAgent   string
+	BaseURL    *url.URL
+	HTTPClient *http.Client
+	Auth       auth.Authenticator
+	UserAgent  string
+
+	// Retry configures automatic retries. A nil policy disables retries.
+	Retry *RetryPolicy
+
+	// MaxBodySize limits the size of buffered response bodies.
+	// Streamed responses (DoStream) are not limited.
+	MaxBodySize int64
 }
 
 // NewTransport creates a Transport with the given configuration.
-func NewTransport(baseURL string, creds *auth.Credentials, httpClient *http.Client) (*Transport, error) {
-	if creds == nil {
-		return nil, fmt.Errorf("credentials must be provided")
+func NewTransport(baseURL string, authenticator auth.Authenticator, httpClient *http.Client) (*Transport, error) {
+	if authenticator == nil {
+		return nil, fmt.Errorf("authenticator must be provided")
 	}
 
 	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
@@ -49,8 +58,9 @@ func NewTransport(baseURL string, creds *auth.Credentials, httpClient *http.Clie
 	return &Transport{
 		BaseURL:     u,
 		HTTPClient:  httpClient,
-		Credentials: creds,
+		Auth:        authenticator,
 		UserAgent:   "go-xsoar/1.0",
+		MaxBodySize: DefaultMaxBodySize,
 	}, nil
 }
 
@@ -60,6 +70,16 @@ type Request struct {
 	Path    string
 	Body    any
 	Headers http.Header
+
+	// RawBody, if set, is sent as-is instead of the JSON-encoded Body.
+	// ContentType must describe its encoding (e.g. a multipart boundary).
+	// Requests with a RawBody are never retried, as the reader cannot be replayed.
+	RawBody     io.Reader
+	ContentType string
+
+	// Idempotent marks a request as safe to retry even if its HTTP method
+	// is not idempotent (e.g. read-only POST search endpoints).
+	Idempotent bool
 }
 
 // Response represents an API response.
@@ -67,10 +87,45 @@ type Response struct {
 	StatusCode int
 	Body       []byte
 	Headers    http.Header
+
+	// Stream holds the unread response body of a successful DoStream call.
+	// The caller must close it.
+	Stream io.ReadCloser
 }
 
 // Do executes an API request and returns the raw response.
+// Failed attempts are retried according to the transport's RetryPolicy
+// when the request is safe to repeat.
 func (t *Transport) Do(ctx context.Context, req *Request) (*Response, error) {
+	return t.doWithRetry(ctx, req, false)
+}
+
+// DoStream executes an API request and returns the response without reading
+// a successful body into memory. The body is available as Response.Stream and
+// must be closed by the caller. Error responses (status >= 400) are buffered
+// into Body as with Do, and Stream is nil.
+func (t *Transport) DoStream(ctx context.Context, req *Request) (*Response, error) {
+	return t.doWithRetry(ctx, req, true)
+}
+
+func (t *Transport) doWithRetry(ctx context.Context, req *Request, stream bool) (*Response, error) {
+	retryable := canRetry(req)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.do(ctx, req, stream)
+		if !retryable {
+			return resp, err
+		}
+
+		delay, retry := t.Retry.retryDelay(attempt, resp, err)
+		if !retry || !sleep(ctx, delay) {
+			return resp, err
+		}
+	}
+}
+
+// do performs a single attempt of an API request.
+func (t *Transport) do(ctx context.Context, req *Request, stream bool) (*Response, error) {
 	httpReq, err := t.buildRequest(ctx, req)
 	if err != nil {
 		return nil, err
@@ -80,17 +135,29 @@ func (t *Transport) Do(ctx context.Context, req *Request) (*Response, error) {
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
+
+	if stream && httpResp.StatusCode < http.StatusBadRequest {
+		return &Response{
+			StatusCode: httpResp.StatusCode,
+			Headers:    httpResp.Header,
+			Stream:     httpResp.Body,
+		}, nil
+	}
 	defer func() { _ = httpResp.Body.Close() }()
 
 	// Limit response body size to prevent memory exhaustion
-	limitedReader := io.LimitReader(httpResp.Body, defaultMaxBodySize+1)
+	maxBodySize := t.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+	limitedReader := io.LimitReader(httpResp.Body, maxBodySize+1)
 	body, err := io.ReadAll(limitedReader)
 	if err != nil {
 		return nil, fmt.Errorf("reading response body: %w", err)
 	}
 
-	if int64(len(body)) > defaultMaxBodySize {
-		return nil, fmt.Errorf("response too large: exceeds %d bytes", defaultMaxBodySize)
+	if int64(len(body)) > maxBodySize {
+		return nil, fmt.Errorf("response too large: exceeds %d bytes", maxBodySize)
 	}
 
 	return &Response{
@@ -122,7 +189,13 @@ func (t *Transport) buildRequest(ctx context.Context, req *Request) (*http.Reque
 	u := t.BaseURL.JoinPath(req.Path)
 
 	var bodyReader io.Reader
-	if req.Body != nil {
+	switch {
+	case req.RawBody != nil:
+		if req.ContentType == "" {
+			return nil, fmt.Errorf("content type must be set for raw request bodies")
+		}
+		bodyReader = req.RawBody
+	case req.Body != nil:
 		data, err := json.Marshal(req.Body)
 		if err != nil {
 			return nil, fmt.Errorf("marshaling request body: %w", err)
@@ -136,14 +209,19 @@ func (t *Transport) buildRequest(ctx context.Context, req *Request) (*http.Reque
 	}
 
 	// Set default headers
-	if req.Body != nil {
+	switch {
+	case req.RawBody != nil:
+		httpReq.Header.Set("Content-Type", req.ContentType)
+	case req.Body != nil:
 		httpReq.Header.Set("Content-Type", "application/json")
 	}
 	httpReq.Header.Set("Accept", "application/json")
 	httpReq.Header.Set("User-Agent", t.UserAgent)
 
 	// Apply authentication
-	t.Credentials.Apply(httpReq)
+	if err := t.Auth.Authenticate(ctx, httpReq); err != nil {
+		return nil, fmt.Errorf("authenticating request: %w", err)
+	}
 
 	// Apply custom headers
 	maps.Copy(httpReq.Header, req.Headers)
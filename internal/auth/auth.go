@@ -1,12 +1,44 @@
 // Package auth provides XSOAR 8.x / XSIAM authentication.
 package auth
 
-import "net/http"
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
 
-// Credentials holds XSOAR 8.x API authentication credentials.
+// nonceLength is the length of the random nonce used by advanced API keys.
+const nonceLength = 64
+
+const nonceAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Authenticator adds authentication to outgoing HTTP requests.
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
+}
+
+// Scheme identifies how API key credentials are sent.
+type Scheme int
+
+const (
+	// SchemeStandard sends the raw XSOAR 8.x key with its key ID.
+	SchemeStandard Scheme = iota
+	// SchemeAdvanced signs each request with a SHA-256 hash of the key,
+	// a fresh nonce, and a timestamp instead of sending the raw key.
+	SchemeAdvanced
+	// SchemeLegacy sends the raw XSOAR 6.x key without a key ID.
+	SchemeLegacy
+)
+
+// Credentials holds XSOAR API key authentication credentials.
 type Credentials struct {
 	KeyID  string
 	APIKey string
+	Scheme Scheme
 }
 
 // Apply adds authentication headers to an HTTP request.
@@ -14,11 +46,45 @@ func (c *Credentials) Apply(req *http.Request) {
 	if c == nil {
 		return
 	}
-	req.Header.Set("x-xdr-auth-id", c.KeyID)
-	req.Header.Set("Authorization", c.APIKey)
+
+	switch c.Scheme {
+	case SchemeLegacy:
+		req.Header.Set("Authorization", c.APIKey)
+	case SchemeAdvanced:
+		nonce := newNonce()
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		sum := sha256.Sum256([]byte(c.APIKey + nonce + timestamp))
+
+		req.Header.Set("x-xdr-auth-id", c.KeyID)
+		req.Header.Set("x-xdr-nonce", nonce)
+		req.Header.Set("x-xdr-timestamp", timestamp)
+		req.Header.Set("Authorization", hex.EncodeToString(sum[:]))
+	default:
+		req.Header.Set("x-xdr-auth-id", c.KeyID)
+		req.Header.Set("Authorization", c.APIKey)
+	}
+}
+
+// Authenticate implements Authenticator.
+func (c *Credentials) Authenticate(_ context.Context, req *http.Request) error {
+	c.Apply(req)
+	return nil
 }
 
 // Valid reports whether credentials are configured.
 func (c *Credentials) Valid() bool {
-	return c != nil && c.KeyID != "" && c.APIKey != ""
+	if c == nil || c.APIKey == "" {
+		return false
+	}
+	return c.Scheme == SchemeLegacy || c.KeyID != ""
+}
+
+// newNonce returns a random alphanumeric string for advanced key signing.
+func newNonce() string {
+	buf := make([]byte, nonceLength)
+	_, _ = rand.Read(buf) // crypto/rand.Read never returns an error
+	for i, b := range buf {
+		buf[i] = nonceAlphabet[int(b)%len(nonceAlphabet)]
+	}
+	return string(buf)
 }
@@ -0,0 +1,258 @@
+package xsoar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// defaultPollInterval is the default delay between polls for async command results.
+const defaultPollInterval = 2 * time.Second
+
+// InvestigationService provides operations on XSOAR investigations.
+//
+//go:generate mockery --name=InvestigationService --output=mocks --outpkg=mocks --filename=investigation_service.go
+type InvestigationService interface {
+	// ExecuteCommand runs a command or automation in an investigation and
+	// waits for its result entries. The command may be given with or without
+	// the leading "!". If any result entry is an error entry, the entries are
+	// returned together with a *CommandError.
+	ExecuteCommand(ctx context.Context, investigationID, command string, args map[string]any, opts ...RequestOption) ([]*Entry, error)
+
+	// ExecuteCommandAsync submits a command without waiting for it to finish.
+	// Use CommandExecution.Wait to poll for the result entries.
+	ExecuteCommandAsync(ctx context.Context, investigationID, command string, args map[string]any, opts ...RequestOption) (*CommandExecution, error)
+}
+
+// investigationService implements InvestigationService.
+type investigationService struct {
+	transport *api.Transport
+	entries   EntryService
+}
+
+func newInvestigationService(transport *api.Transport, entries EntryService) *investigationService {
+	return &investigationService{transport: transport, entries: entries}
+}
+
+// ExecuteCommand runs a command in an investigation and returns its result entries.
+func (s *investigationService) ExecuteCommand(ctx context.Context, investigationID, command string, args map[string]any, opts ...RequestOption) ([]*Entry, error) {
+	data, err := prepareCommand(investigationID, command, args)
+	if err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result []*Entry
+	err = doRequest(ctx, s.transport, &api.Request{
+		Method: http.MethodPost,
+		Path:   "/entry/execute/sync",
+		Body: map[string]any{
+			"investigationId": investigationID,
+			"data":            data,
+		},
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, &result)
+	if err != nil {
+		return nil, withResource(err, "investigation", investigationID)
+	}
+
+	return result, commandError(command, result)
+}
+
+// ExecuteCommandAsync submits a command without waiting for it to finish.
+func (s *investigationService) ExecuteCommandAsync(ctx context.Context, investigationID, command string, args map[string]any, opts ...RequestOption) (*CommandExecution, error) {
+	data, err := prepareCommand(investigationID, command, args)
+	if err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result Entry
+	err = doRequest(ctx, s.transport, &api.Request{
+		Method: http.MethodPost,
+		Path:   "/entry",
+		Body: map[string]any{
+			"investigationId": investigationID,
+			"data":            data,
+		},
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, &result)
+	if err != nil {
+		return nil, withResource(err, "investigation", investigationID)
+	}
+
+	return &CommandExecution{
+		InvestigationID: investigationID,
+		EntryID:         result.ID,
+		Command:         command,
+		submitted:       result.Created,
+		entries:         s.entries,
+		opts:            opts,
+	}, nil
+}
+
+// CommandExecution tracks a command submitted with ExecuteCommandAsync.
+type CommandExecution struct {
+	// InvestigationID is the investigation the command runs in.
+	InvestigationID string
+
+	// EntryID is the ID of the War Room entry holding the command.
+	EntryID string
+
+	// Command is the submitted command.
+	Command string
+
+	submitted time.Time
+	entries   EntryService
+	opts      []RequestOption
+}
+
+// Wait polls the War Room every interval until the command's result
+// entries have settled, then returns them. The API has no completion marker
+// for commands, and a command may write several result entries, so the
+// results are considered complete once at least one has appeared and a
+// further poll finds no new ones. A non-positive interval uses a default
+// of two seconds. If any result entry is an error entry, the entries are
+// returned together with a *CommandError.
+//
+// Wait has no time limit of its own: a command that writes no entries is
+// polled until ctx is done, so ctx should carry a deadline. Because
+// completion is inferred, a command that writes a progress entry and then
+// takes longer than one interval to write its final entry is returned
+// early with only the progress entry; choose an interval longer than the
+// gaps between the command's entries.
+func (c *CommandExecution) Wait(ctx context.Context, interval time.Duration) ([]*Entry, error) {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	seen := 0
+	for {
+		results, err := c.poll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) > 0 && len(results) == seen {
+			return results, commandError(c.Command, results)
+		}
+		seen = len(results)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll returns the entries produced by the command so far.
+func (c *CommandExecution) poll(ctx context.Context) ([]*Entry, error) {
+	var results []*Entry
+	filter := &EntryFilter{FromTime: c.submitted}
+	for entry, err := range c.entries.List(ctx, c.InvestigationID, filter, c.opts...) {
+		if err != nil {
+			return nil, err
+		}
+		if entry.ParentID == c.EntryID {
+			results = append(results, entry)
+		}
+	}
+	return results, nil
+}
+
+// prepareCommand validates the arguments of a command execution and
+// renders the War Room command line.
+func prepareCommand(investigationID, command string, args map[string]any) (string, error) {
+	if err := validateRequired("investigation ID", investigationID); err != nil {
+		return "", err
+	}
+
+	name := strings.TrimPrefix(strings.TrimSpace(command), "!")
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		return "", &ValidationError{
+			APIError: APIError{Message: fmt.Sprintf("invalid command name: %q", command)},
+		}
+	}
+
+	return formatCommand(name, args)
+}
+
+// formatCommand renders a War Room command line such as
+// !ip ip="1.1.1.1" verbose=true. Arguments are sorted by name so the
+// output is deterministic.
+func formatCommand(name string, args map[string]any) (string, error) {
+	var b strings.Builder
+	b.WriteString("!")
+	b.WriteString(name)
+
+	for _, key := range slices.Sorted(maps.Keys(args)) {
+		value, err := formatCommandArg(args[key])
+		if err != nil {
+			return "", &ValidationError{
+				APIError: APIError{Message: fmt.Sprintf("invalid value for argument %q: %v", key, err)},
+			}
+		}
+		b.WriteString(" ")
+		b.WriteString(key)
+		b.WriteString("=")
+		b.WriteString(value)
+	}
+
+	return b.String(), nil
+}
+
+// formatCommandArg renders a single argument value. Strings and structured
+// values are quoted; numbers and booleans are written as-is.
+func formatCommandArg(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return `""`, nil
+	case string:
+		return quoteCommandArg(v), nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return quoteCommandArg(string(data)), nil
+	}
+}
+
+// quoteCommandArg wraps a value in double quotes, escaping backslashes and quotes.
+func quoteCommandArg(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// commandError returns a *CommandError for the first error entry, if any.
+func commandError(command string, entries []*Entry) error {
+	for _, entry := range entries {
+		if entry.IsError() {
+			return &CommandError{
+				Command: command,
+				EntryID: entry.ID,
+				Message: entry.ContentsString(),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+func TestInvestigationService_ExecuteCommand(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/entry/execute/sync", r.URL.Path)
+
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, "inv-1", reqBody["investigationId"])
+			assert.Equal(t, `!ip ip="1.1.1.1" note="say \"hi\"" tags="[\"a\",\"b\"]" verbose=true`, reqBody["data"])
+
+			entries := []*xsoar.Entry{
+				{ID: "2@inv-1", Type: xsoar.EntryTypeNote, ContentsFormat: xsoar.EntryFormatJSON, Contents: map[string]any{"score": 1}},
+				{ID: "3@inv-1", Type: xsoar.EntryTypeFile, File: "report.pdf", FileMetadata: &xsoar.FileMetadata{Size: 2048}},
+			}
+			err = json.NewEncoder(w).Encode(entries)
+			assert.NoError(t, err)
+		})
+
+		entries, err := client.Investigations.ExecuteCommand(context.Background(), "inv-1", "!ip", map[string]any{
+			"ip":      "1.1.1.1",
+			"verbose": true,
+			"note":    `say "hi"`,
+			"tags":    []string{"a", "b"},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, entries, 2)
+		assert.JSONEq(t, `{"score":1}`, entries[0].ContentsString())
+		assert.Equal(t, "report.pdf", entries[1].File)
+		assert.Equal(t, int64(2048), entries[1].FileMetadata.Size)
+	})
+
+	t.Run("error entry returns command error", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			entries := []*xsoar.Entry{
+				{ID: "2@inv-1", Type: xsoar.EntryTypeError, Contents: "Unsupported command"},
+			}
+			err := json.NewEncoder(w).Encode(entries)
+			assert.NoError(t, err)
+		})
+
+		entries, err := client.Investigations.ExecuteCommand(context.Background(), "inv-1", "bogus", nil)
+		require.Error(t, err)
+		assert.Len(t, entries, 1)
+
+		var cmdErr *xsoar.CommandError
+		require.ErrorAs(t, err, &cmdErr)
+		assert.Equal(t, "bogus", cmdErr.Command)
+		assert.Equal(t, "2@inv-1", cmdErr.EntryID)
+		assert.Equal(t, "Unsupported command", cmdErr.Message)
+	})
+
+	t.Run("invalid command returns validation error", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call with invalid command")
+		})
+
+		for _, command := range []string{"", "!", "ip ip=1.1.1.1"} {
+			_, err := client.Investigations.ExecuteCommand(context.Background(), "inv-1", command, nil)
+
+			var validationErr *xsoar.ValidationError
+			require.ErrorAs(t, err, &validationErr, "command %q", command)
+		}
+	})
+}
+
+func TestInvestigationService_ExecuteCommandAsync(t *testing.T) {
+	var polls atomic.Int32
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var response any
+		switch r.URL.Path {
+		case "/entry":
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, "!Sleep seconds=5", reqBody["data"])
+			response = xsoar.Entry{ID: "10@inv-1", Created: time.Now()}
+		case "/investigation/inv-1":
+			// Results arrive over several polls: none, then one, then two.
+			entries := []*xsoar.Entry{{ID: "10@inv-1"}}
+			poll := polls.Add(1)
+			if poll > 1 {
+				entries = append(entries, &xsoar.Entry{ID: "11@inv-1", ParentID: "10@inv-1", Contents: "partial"})
+			}
+			if poll > 2 {
+				entries = append(entries, &xsoar.Entry{ID: "12@inv-1", ParentID: "10@inv-1", Contents: "done"})
+			}
+			response = map[string]any{"entries": entries, "total": len(entries)}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		err := json.NewEncoder(w).Encode(response)
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+	exec, err := client.Investigations.ExecuteCommandAsync(ctx, "inv-1", "Sleep", map[string]any{"seconds": 5})
+	require.NoError(t, err)
+	assert.Equal(t, "10@inv-1", exec.EntryID)
+
+	entries, err := exec.Wait(ctx, time.Millisecond)
+	require.NoError(t, err)
+
+	require.Len(t, entries, 2, "Wait returns only once results stop growing")
+	assert.Equal(t, "done", entries[1].ContentsString())
+	assert.Equal(t, int32(4), polls.Load())
+}
@@ -1,6 +1,7 @@
 package xsoar
 
 import (
+	"context"
 	"errors"
 	"iter"
 	"slices"
@@ -101,3 +102,19 @@ func Map[T, U any](seq iter.Seq2[T, error], fn func(T) U) iter.Seq2[U, error] {
 func ToSlice[T any](seq iter.Seq[T]) []T {
 	return slices.Collect(seq)
 }
+
+// yieldItems yields each item to an iterator, checking for context
+// cancellation between items. It returns false if iteration should stop.
+func yieldItems[T any](ctx context.Context, items []T, yield func(T, error) bool) bool {
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+			return false
+		}
+		if !yield(item, nil) {
+			return false
+		}
+	}
+	return true
+}
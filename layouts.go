@@ -0,0 +1,129 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// LayoutService provides operations on incident and indicator layouts.
+//
+//go:generate mockery --name=LayoutService --output=mocks --outpkg=mocks --filename=layout_service.go
+type LayoutService interface {
+	// List returns all layouts.
+	List(ctx context.Context, opts ...RequestOption) ([]*Layout, error)
+
+	// Get retrieves a layout by ID.
+	Get(ctx context.Context, id string, opts ...RequestOption) (*Layout, error)
+
+	// Save creates a layout or updates an existing one with the same ID.
+	// Assign it to an incident type by setting IncidentType.Layout.
+	Save(ctx context.Context, layout *Layout, opts ...RequestOption) (*Layout, error)
+
+	// Delete removes a layout by ID.
+	Delete(ctx context.Context, id string, opts ...RequestOption) error
+}
+
+// layoutService implements LayoutService.
+type layoutService struct {
+	transport *api.Transport
+}
+
+func newLayoutService(transport *api.Transport) *layoutService {
+	return &layoutService{transport: transport}
+}
+
+// List returns all layouts.
+func (s *layoutService) List(ctx context.Context, opts ...RequestOption) ([]*Layout, error) {
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result []*Layout
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodGet,
+		Path:       "/layouts",
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Get retrieves a layout by ID.
+func (s *layoutService) Get(ctx context.Context, id string, opts ...RequestOption) (*Layout, error) {
+	if err := validateRequired("layout ID", id); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result Layout
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodGet,
+		Path:       fmt.Sprintf("/layout/%s", url.PathEscape(id)),
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, &result)
+	if err != nil {
+		return nil, withResource(err, "layout", id)
+	}
+
+	return &result, nil
+}
+
+// Save creates or updates a layout.
+func (s *layoutService) Save(ctx context.Context, layout *Layout, opts ...RequestOption) (*Layout, error) {
+	if layout == nil {
+		return nil, &ValidationError{APIError: APIError{Message: "layout cannot be nil"}}
+	}
+	if err := validateRequired("layout name", layout.Name); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	body := *layout
+	if body.Group == "" {
+		body.Group = "incident"
+	}
+
+	var result Layout
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/layouts/save",
+		Body:       &body,
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Delete removes a layout by ID.
+func (s *layoutService) Delete(ctx context.Context, id string, opts ...RequestOption) error {
+	if err := validateRequired("layout ID", id); err != nil {
+		return err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       fmt.Sprintf("/layout/%s/remove", url.PathEscape(id)),
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, nil)
+	return withResource(err, "layout", id)
+}
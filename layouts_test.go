@@ -0,0 +1,72 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+func TestLayoutService_Get(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/layout/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		assert.Equal(t, "/layout/Phishing%20Layout", r.URL.EscapedPath())
+		_, err := w.Write([]byte(`{"id": "Phishing Layout", "name": "Phishing Layout", "group": "incident", "detailsV2": {"tabs": []}}`))
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+
+	layout, err := client.Layouts.Get(ctx, "Phishing Layout")
+	require.NoError(t, err)
+	assert.Equal(t, "incident", layout.Group)
+	assert.JSONEq(t, `{"tabs": []}`, string(layout.DetailsV2))
+
+	_, err = client.Layouts.Get(ctx, "missing")
+	var notFoundErr *xsoar.NotFoundError
+	require.ErrorAs(t, err, &notFoundErr)
+	assert.Equal(t, "layout", notFoundErr.ResourceType)
+}
+
+func TestLayoutService_Save(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/layouts/save", r.URL.Path)
+
+		var layout xsoar.Layout
+		err := json.NewDecoder(r.Body).Decode(&layout)
+		assert.NoError(t, err)
+		assert.Equal(t, "incident", layout.Group)
+
+		layout.ID = layout.Name
+		err = json.NewEncoder(w).Encode(layout)
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+	layout, err := client.Layouts.Save(ctx, &xsoar.Layout{
+		Name:      "Ransomware Layout",
+		DetailsV2: json.RawMessage(`{"tabs": []}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Ransomware Layout", layout.ID)
+}
+
+func TestLayoutService_Delete(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/layout/Ransomware%20Layout/remove", r.URL.EscapedPath())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ctx := context.Background()
+	require.NoError(t, client.Layouts.Delete(ctx, "Ransomware Layout"))
+}
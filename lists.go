@@ -0,0 +1,232 @@
+package xsoar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// ListService provides operations on XSOAR lists.
+//
+//go:generate mockery --name=ListService --output=mocks --outpkg=mocks --filename=list_service.go
+type ListService interface {
+	// List returns all lists, including their content.
+	List(ctx context.Context, opts ...RequestOption) ([]*List, error)
+
+	// Get retrieves the content of a list by name. Only the list's own
+	// content is downloaded; ID and Name are set to name, and Type,
+	// Version and the other metadata are not populated. Use List to read
+	// them.
+	Get(ctx context.Context, name string, opts ...RequestOption) (*List, error)
+
+	// Save creates a list or replaces an existing one with the same name.
+	// If list.Version is set, the server rejects the write with a
+	// ConflictError when the list has been modified since it was read.
+	Save(ctx context.Context, list *List, opts ...RequestOption) (*List, error)
+
+	// Delete removes a list by name.
+	Delete(ctx context.Context, name string, opts ...RequestOption) error
+
+	// AppendIfAbsent adds item as a new line to a newline-separated list
+	// unless a line with the same value already exists. It reports whether
+	// the item was added. The read-modify-write is guarded by the list
+	// version and retried on conflict, so concurrent appends are not lost.
+	// Only plain-text lists can be appended to; other types fail with a
+	// ValidationError.
+	//
+	// The API returns list versions only with the full set of lists, so
+	// when the item has to be added each attempt downloads every list.
+	// Tenants whose lists add up to more than the client's maximum
+	// response size (10MB by default) need WithMaxResponseSize.
+	AppendIfAbsent(ctx context.Context, name, item string, opts ...RequestOption) (bool, error)
+}
+
+// listService implements ListService.
+type listService struct {
+	transport *api.Transport
+}
+
+func newListService(transport *api.Transport) *listService {
+	return &listService{transport: transport}
+}
+
+// List returns all lists.
+func (s *listService) List(ctx context.Context, opts ...RequestOption) ([]*List, error) {
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result []*List
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodGet,
+		Path:       "/lists",
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Get retrieves the content of a list by name.
+func (s *listService) Get(ctx context.Context, name string, opts ...RequestOption) (*List, error) {
+	if err := validateRequired("list name", name); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	// The content is returned as-is rather than as JSON.
+	resp, err := s.transport.Do(ctx, &api.Request{
+		Method:     http.MethodGet,
+		Path:       fmt.Sprintf("/lists/download/%s", url.PathEscape(name)),
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, withResource(parseError(resp.StatusCode, resp.Body, resp.Headers), "list", name)
+	}
+
+	return &List{ID: name, Name: name, Data: string(resp.Body)}, nil
+}
+
+// lookup retrieves a list with its metadata and version by name or ID.
+// The API has no endpoint returning a single list with its version, so the
+// list is picked from the full set.
+func (s *listService) lookup(ctx context.Context, name string, opts []RequestOption) (*List, error) {
+	lists, err := s.List(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(lists, func(list *List) bool {
+		return list.Name == name || list.ID == name
+	})
+	if index < 0 {
+		return nil, &NotFoundError{
+			APIError:     APIError{StatusCode: http.StatusNotFound, Message: "list not found"},
+			ResourceType: "list",
+			ResourceID:   name,
+		}
+	}
+
+	return lists[index], nil
+}
+
+// Save creates or replaces a list.
+func (s *listService) Save(ctx context.Context, list *List, opts ...RequestOption) (*List, error) {
+	if list == nil {
+		return nil, &ValidationError{APIError: APIError{Message: "list cannot be nil"}}
+	}
+	if err := validateRequired("list name", list.Name); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	body := *list
+	if body.ID == "" {
+		body.ID = body.Name
+	}
+	if body.Type == "" {
+		body.Type = ListTypePlainText
+	}
+
+	var result List
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/lists/save",
+		Body:       &body,
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Delete removes a list by name.
+func (s *listService) Delete(ctx context.Context, name string, opts ...RequestOption) error {
+	if err := validateRequired("list name", name); err != nil {
+		return err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/lists/delete",
+		Body:       map[string]any{"id": name},
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, nil)
+	return withResource(err, "list", name)
+}
+
+// AppendIfAbsent adds a line to a list unless it is already present.
+func (s *listService) AppendIfAbsent(ctx context.Context, name, item string, opts ...RequestOption) (bool, error) {
+	item = strings.TrimSpace(item)
+	if err := validateRequired("item", item); err != nil {
+		return false, err
+	}
+	if strings.ContainsAny(item, "\r\n") {
+		return false, &ValidationError{APIError: APIError{Message: "item cannot contain line breaks"}}
+	}
+
+	for attempt := 1; ; attempt++ {
+		// Check the list's own content first; the full listing carrying
+		// the version is only read when the item has to be added.
+		content, err := s.Get(ctx, name, opts...)
+		if err != nil {
+			return false, err
+		}
+		if slices.Contains(content.Lines(), item) {
+			return false, nil
+		}
+
+		list, err := s.lookup(ctx, name, opts)
+		if err != nil {
+			return false, err
+		}
+		if list.Type != "" && list.Type != ListTypePlainText {
+			return false, &ValidationError{
+				APIError: APIError{Message: fmt.Sprintf("cannot append lines to list %q of type %s", name, list.Type)},
+			}
+		}
+		if slices.Contains(list.Lines(), item) {
+			return false, nil
+		}
+
+		if list.Data != "" && !strings.HasSuffix(list.Data, "\n") {
+			list.Data += "\n"
+		}
+		list.Data += item
+
+		_, err = s.Save(ctx, list, opts...)
+
+		var conflictErr *ConflictError
+		switch {
+		case errors.As(err, &conflictErr) && attempt < maxConflictRetries:
+			continue
+		case err != nil:
+			return false, err
+		default:
+			return true, nil
+		}
+	}
+}
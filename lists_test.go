@@ -0,0 +1,196 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+func TestListService_Get(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		switch r.URL.EscapedPath() {
+		case "/lists/download/Allowed%20IPs":
+			_, err := w.Write([]byte("10.0.0.1\n10.0.0.2"))
+			assert.NoError(t, err)
+		case "/lists":
+			t.Error("Get should not download every list")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	ctx := context.Background()
+
+	t.Run("found", func(t *testing.T) {
+		list, err := client.Lists.Get(ctx, "Allowed IPs")
+		require.NoError(t, err)
+		assert.Equal(t, "Allowed IPs", list.Name)
+		assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, list.Lines())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := client.Lists.Get(ctx, "missing")
+		var notFoundErr *xsoar.NotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "list", notFoundErr.ResourceType)
+		assert.Equal(t, "missing", notFoundErr.ResourceID)
+	})
+}
+
+func TestListService_Save(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/lists/save", r.URL.Path)
+
+		var list xsoar.List
+		err := json.NewDecoder(r.Body).Decode(&list)
+		assert.NoError(t, err)
+		assert.Equal(t, "allowlist", list.ID)
+		assert.Equal(t, xsoar.ListTypePlainText, list.Type)
+
+		list.Version = 1
+		err = json.NewEncoder(w).Encode(list)
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+	list, err := client.Lists.Save(ctx, &xsoar.List{Name: "allowlist", Data: "10.0.0.1"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, list.Version)
+
+	_, err = client.Lists.Save(ctx, &xsoar.List{})
+	var validationErr *xsoar.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func TestListService_Delete(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/lists/delete", r.URL.Path)
+
+		var reqBody map[string]any
+		err := json.NewDecoder(r.Body).Decode(&reqBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "allowlist", reqBody["id"])
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ctx := context.Background()
+	err := client.Lists.Delete(ctx, "allowlist")
+	require.NoError(t, err)
+}
+
+// listServer is a fake list store that rejects saves with a stale version.
+type listServer struct {
+	mu       sync.Mutex
+	list     xsoar.List
+	saves    int
+	listings int
+
+	// interfere simulates a concurrent writer before the n-th save.
+	interfere map[int]string
+}
+
+func (s *listServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch r.URL.Path {
+		case "/lists":
+			s.listings++
+			err := json.NewEncoder(w).Encode([]xsoar.List{s.list})
+			assert.NoError(t, err)
+		case "/lists/download/" + s.list.Name:
+			_, err := w.Write([]byte(s.list.Data))
+			assert.NoError(t, err)
+		case "/lists/save":
+			var list xsoar.List
+			err := json.NewDecoder(r.Body).Decode(&list)
+			assert.NoError(t, err)
+
+			s.saves++
+			if line, ok := s.interfere[s.saves]; ok {
+				s.list.Data += "\n" + line
+				s.list.Version++
+			}
+			if list.Version != s.list.Version {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			list.Version++
+			s.list = list
+			err = json.NewEncoder(w).Encode(list)
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestListService_AppendIfAbsent(t *testing.T) {
+	t.Run("appends new item", func(t *testing.T) {
+		server := &listServer{list: xsoar.List{ID: "allow", Name: "allow", Data: "a\nb", Version: 1}}
+		client := setupTestServer(t, server.handler(t))
+
+		ctx := context.Background()
+		added, err := client.Lists.AppendIfAbsent(ctx, "allow", "c")
+		require.NoError(t, err)
+		assert.True(t, added)
+		assert.Equal(t, "a\nb\nc", server.list.Data)
+	})
+
+	t.Run("skips existing item", func(t *testing.T) {
+		server := &listServer{list: xsoar.List{ID: "allow", Name: "allow", Data: "a\n b \n", Version: 1}}
+		client := setupTestServer(t, server.handler(t))
+
+		ctx := context.Background()
+		added, err := client.Lists.AppendIfAbsent(ctx, "allow", "b")
+		require.NoError(t, err)
+		assert.False(t, added)
+		assert.Zero(t, server.saves)
+		assert.Zero(t, server.listings, "existing items are found without the full listing")
+	})
+
+	t.Run("rejects lists that are not plain text", func(t *testing.T) {
+		server := &listServer{list: xsoar.List{ID: "config", Name: "config", Data: `{"a": 1}`, Type: xsoar.ListTypeJSON, Version: 1}}
+		client := setupTestServer(t, server.handler(t))
+
+		ctx := context.Background()
+		_, err := client.Lists.AppendIfAbsent(ctx, "config", "c")
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Zero(t, server.saves)
+		assert.JSONEq(t, `{"a": 1}`, server.list.Data)
+	})
+
+	t.Run("keeps concurrent changes", func(t *testing.T) {
+		server := &listServer{
+			list:      xsoar.List{ID: "allow", Name: "allow", Data: "a", Version: 1},
+			interfere: map[int]string{1: "x"},
+		}
+		client := setupTestServer(t, server.handler(t))
+
+		ctx := context.Background()
+		added, err := client.Lists.AppendIfAbsent(ctx, "allow", "c")
+		require.NoError(t, err)
+		assert.True(t, added)
+		assert.Equal(t, "a\nx\nc", server.list.Data)
+		assert.Equal(t, 2, server.saves)
+	})
+
+	t.Run("rejects multi-line items", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call for an invalid item")
+		})
+
+		ctx := context.Background()
+		_, err := client.Lists.AppendIfAbsent(ctx, "allow", "a\nb")
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
@@ -1,7 +1,15 @@
 package xsoar
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -71,6 +79,15 @@ type Label struct {
 	Value string `json:"value"`
 }
 
+// Attachment describes a file attached to an incident.
+type Attachment struct {
+	Name          string `json:"name"`
+	Path          string `json:"path"`
+	Type          string `json:"type,omitempty"`
+	Description   string `json:"description,omitempty"`
+	ShowMediaFile bool   `json:"showMediaFile,omitempty"`
+}
+
 // Incident represents an XSOAR incident.
 type Incident struct {
 	ID            string         `json:"id"`
@@ -84,19 +101,115 @@ type Incident struct {
 	PlaybookID    string         `json:"playbookId,omitempty"`
 	InvestigateID string         `json:"investigationId,omitempty"`
 
+	// Version is incremented by the server on every change. Sending it back
+	// on update makes the server reject the write if the incident changed
+	// in the meantime.
+	Version int `json:"version,omitempty"`
+
 	Created  time.Time `json:"created"`
 	Modified time.Time `json:"modified"`
 	Closed   time.Time `json:"closed,omitzero"` // Go 1.24+: omit when zero
 
 	Labels []Label `json:"labels,omitempty"`
 
+	// Attachments lists files attached to the incident's attachment field.
+	Attachments []Attachment `json:"attachment,omitempty"`
+
 	// CustomFields holds customer-defined incident fields.
 	CustomFields map[string]any `json:"CustomFields,omitempty"`
 
-	// RawData captures any fields not explicitly modeled.
+	// RawData captures any fields not explicitly modeled, such as sla,
+	// dbotMirrorId, or sourceBrand. It is populated when decoding and merged
+	// back into the JSON object when encoding, so unmapped data survives a
+	// read-modify-write cycle. Numbers are decoded as json.Number to keep
+	// their exact value.
 	RawData map[string]any `json:"-"`
 }
 
+// incidentJSON has the fields of Incident without its JSON methods.
+type incidentJSON Incident
+
+// incidentKeys holds the lower-cased JSON keys mapped to Incident fields.
+// encoding/json matches keys case-insensitively, so lookups must too.
+var incidentKeys = sync.OnceValue(func() map[string]bool {
+	return jsonKeys(reflect.TypeFor[Incident]())
+})
+
+// jsonKeys returns the lower-cased JSON keys of a struct type's exported fields.
+func jsonKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool, t.NumField())
+	for idx := range t.NumField() {
+		field := t.Field(idx)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		keys[strings.ToLower(name)] = true
+	}
+	return keys
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Keys that do not map to a field are collected in RawData.
+func (i *Incident) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*incidentJSON)(i)); err != nil {
+		return err
+	}
+
+	var raw map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+
+	i.RawData = nil
+	known := incidentKeys()
+	for key, value := range raw {
+		if known[strings.ToLower(key)] {
+			continue
+		}
+		if i.RawData == nil {
+			i.RawData = make(map[string]any)
+		}
+		i.RawData[key] = value
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// Entries in RawData are written alongside the modeled fields; modeled
+// fields take precedence on conflicting keys.
+func (i Incident) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal((*incidentJSON)(&i))
+	if err != nil || len(i.RawData) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]any, len(fields)+len(i.RawData))
+	known := incidentKeys()
+	for key, value := range i.RawData {
+		if !known[strings.ToLower(key)] {
+			merged[key] = value
+		}
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return json.Marshal(merged)
+}
+
 // IncidentFilter defines search criteria for incidents.
 type IncidentFilter struct {
 	// Query is a Lucene-style query string.
@@ -119,6 +232,16 @@ type IncidentFilter struct {
 
 	// ToDate filters incidents created before this time.
 	ToDate time.Time `json:"toDate,omitzero"`
+
+	// Sort orders the results. Search appends an ascending "id" sort as a
+	// tiebreaker so that pages do not overlap when sort values are equal.
+	Sort []SortField `json:"sort,omitempty"`
+}
+
+// SortField orders search results by a field.
+type SortField struct {
+	Field string `json:"field"`
+	Asc   bool   `json:"asc"`
 }
 
 // PageOptions configures pagination for search requests.
@@ -164,6 +287,11 @@ type UpdateIncidentRequest struct {
 	Status       *IncidentStatus `json:"status,omitempty"`
 	Description  *string         `json:"description,omitempty"`
 	CustomFields map[string]any  `json:"CustomFields,omitempty"`
+
+	// Version, if set, is the incident version the update is based on. The
+	// server rejects the update with a ConflictError if the incident has
+	// been modified since.
+	Version *int `json:"version,omitempty"`
 }
 
 // CloseIncidentRequest contains data for closing an incident.
@@ -173,8 +301,1079 @@ type CloseIncidentRequest struct {
 	CloseDate time.Time `json:"closeDate,omitzero"`
 }
 
+// investigateResponse is the response format of /incident/investigate.
+type investigateResponse struct {
+	ID string `json:"id"`
+}
+
 // searchRequest is the internal request format for incident search.
 type searchRequest struct {
 	Filter *IncidentFilter `json:"filter,omitempty"`
 	PageOptions
+	Fields []string `json:"fields,omitempty"`
+}
+
+// EntryType identifies the kind of a War Room entry.
+type EntryType int
+
+const (
+	EntryTypeNote          EntryType = 1
+	EntryTypeFile          EntryType = 3
+	EntryTypeError         EntryType = 4
+	EntryTypeImage         EntryType = 7
+	EntryTypeEntryInfoFile EntryType = 9
+	EntryTypeWarning       EntryType = 11
+)
+
+// EntryFormat is the format of an entry's contents.
+type EntryFormat string
+
+const (
+	EntryFormatText     EntryFormat = "text"
+	EntryFormatMarkdown EntryFormat = "markdown"
+	EntryFormatHTML     EntryFormat = "html"
+	EntryFormatTable    EntryFormat = "table"
+	EntryFormatJSON     EntryFormat = "json"
+)
+
+// FileMetadata describes a file attached to an entry.
+type FileMetadata struct {
+	Size   int64  `json:"size"`
+	Type   string `json:"type,omitempty"`
+	Info   string `json:"info,omitempty"`
+	MD5    string `json:"md5,omitempty"`
+	SHA1   string `json:"sha1,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// Entry represents a War Room entry in an investigation.
+type Entry struct {
+	ID              string      `json:"id"`
+	InvestigationID string      `json:"investigationId"`
+	Type            EntryType   `json:"type"`
+	User            string      `json:"user,omitempty"`
+	ParentID        string      `json:"parentId,omitempty"`
+	Note            bool        `json:"note"`
+	Tags            []string    `json:"tags,omitempty"`
+	Category        string      `json:"category,omitempty"`
+	ContentsFormat  EntryFormat `json:"contentsFormat,omitempty"`
+
+	// Contents holds the entry body. It is a string for text entries and
+	// a decoded JSON value for structured entries.
+	Contents any `json:"contents"`
+
+	// File fields are set for file and image entries.
+	File         string        `json:"file,omitempty"`
+	FileID       string        `json:"fileID,omitempty"`
+	FileMetadata *FileMetadata `json:"fileMetadata,omitempty"`
+
+	Created  time.Time `json:"created"`
+	Modified time.Time `json:"modified"`
+}
+
+// IsError reports whether the entry is an error entry.
+func (e *Entry) IsError() bool {
+	return e.Type == EntryTypeError
+}
+
+// ContentsString returns the entry contents as text.
+// Structured contents are rendered as JSON.
+func (e *Entry) ContentsString() string {
+	switch v := e.Contents.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
+
+// EntryFilter defines criteria for listing investigation entries.
+type EntryFilter struct {
+	// Categories filters by entry category (e.g. "notes", "chats", "commandAndResults").
+	Categories []string `json:"categories,omitempty"`
+
+	// Tags filters by entry tags.
+	Tags []string `json:"tags,omitempty"`
+
+	// Users filters by the user who created the entry.
+	Users []string `json:"users,omitempty"`
+
+	// FromTime filters entries created after this time.
+	FromTime time.Time `json:"fromTime,omitzero"`
+}
+
+// AddEntryRequest contains data for adding a formatted entry to the War Room.
+type AddEntryRequest struct {
+	InvestigationID string      `json:"investigationId"`
+	Format          EntryFormat `json:"format"`
+	Contents        string      `json:"contents"`
+}
+
+// EvidenceRequest contains data for marking an entry as evidence.
+type EvidenceRequest struct {
+	InvestigationID string    `json:"investigationId"`
+	EntryID         string    `json:"entryId"`
+	Description     string    `json:"description,omitempty"`
+	Tags            []string  `json:"tags,omitempty"`
+	Occurred        time.Time `json:"occurred,omitzero"`
+}
+
+// entryListRequest is the internal request format for listing entries.
+type entryListRequest struct {
+	*EntryFilter
+	PageSize int    `json:"pageSize"`
+	LastID   string `json:"lastId,omitempty"`
+}
+
+// entryListResponse is the internal response format for listing entries.
+type entryListResponse struct {
+	Entries []*Entry `json:"entries"`
+	Total   int      `json:"total"`
+}
+
+// IndicatorScore represents the DBot reputation score of an indicator.
+type IndicatorScore int
+
+const (
+	ScoreUnknown    IndicatorScore = 0
+	ScoreGood       IndicatorScore = 1
+	ScoreSuspicious IndicatorScore = 2
+	ScoreBad        IndicatorScore = 3
+)
+
+func (s IndicatorScore) String() string {
+	switch s {
+	case ScoreGood:
+		return "Good"
+	case ScoreSuspicious:
+		return "Suspicious"
+	case ScoreBad:
+		return "Bad"
+	default:
+		return "Unknown"
+	}
+}
+
+// IndicatorComment represents a comment on an indicator.
+type IndicatorComment struct {
+	ID       string    `json:"id,omitempty"`
+	Content  string    `json:"content"`
+	User     string    `json:"user,omitempty"`
+	Type     string    `json:"type,omitempty"`
+	Created  time.Time `json:"created,omitzero"`
+	Modified time.Time `json:"modified,omitzero"`
+}
+
+// Indicator represents an XSOAR threat intel indicator.
+type Indicator struct {
+	ID            string         `json:"id,omitempty"`
+	Value         string         `json:"value"`
+	IndicatorType string         `json:"indicator_type"`
+	Score         IndicatorScore `json:"score"`
+	Version       int            `json:"version,omitempty"`
+
+	Expiration       time.Time `json:"expiration,omitzero"`
+	ExpirationStatus string    `json:"expirationStatus,omitempty"`
+	FirstSeen        time.Time `json:"firstSeen,omitzero"`
+	LastSeen         time.Time `json:"lastSeen,omitzero"`
+	Timestamp        time.Time `json:"timestamp,omitzero"`
+	Modified         time.Time `json:"modified,omitzero"`
+
+	// Sources lists the integrations that reported the indicator.
+	Sources []string `json:"sourceBrands,omitempty"`
+
+	Comments []IndicatorComment `json:"comments,omitempty"`
+
+	// CustomFields holds indicator fields such as tags and custom fields.
+	CustomFields map[string]any `json:"CustomFields,omitempty"`
+}
+
+// IndicatorFilter defines search criteria for indicators.
+type IndicatorFilter struct {
+	// Query is a Lucene-style query string, e.g. `type:IP and score:3`.
+	Query string `json:"query,omitempty"`
+
+	// FromDate filters indicators modified after this time.
+	FromDate time.Time `json:"fromDate,omitzero"`
+
+	// ToDate filters indicators modified before this time.
+	ToDate time.Time `json:"toDate,omitzero"`
+}
+
+// IndicatorPage represents a page of indicator results.
+type IndicatorPage struct {
+	Data   []*Indicator `json:"iocObjects"`
+	Total  int          `json:"total"`
+	Offset int          `json:"-"`
+}
+
+// HasMore returns true if there are more pages available.
+func (p *IndicatorPage) HasMore() bool {
+	return len(p.Data) > 0 && p.Offset+len(p.Data) < p.Total
+}
+
+// NextOffset returns the offset for the next page.
+func (p *IndicatorPage) NextOffset() int {
+	return p.Offset + len(p.Data)
+}
+
+// ExcludeIndicatorRequest contains data for adding a value to the exclusion list.
+type ExcludeIndicatorRequest struct {
+	Value         string `json:"value"`
+	IndicatorType string `json:"type,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// indicatorSearchRequest is the internal request format for indicator search.
+// The indicators API paginates by page number rather than offset.
+type indicatorSearchRequest struct {
+	*IndicatorFilter
+	Page int `json:"page"`
+	Size int `json:"size"`
+}
+
+// BulkItemStatus is the outcome of a single item in a bulk operation.
+type BulkItemStatus string
+
+const (
+	BulkItemCreated  BulkItemStatus = "created"
+	BulkItemUpdated  BulkItemStatus = "updated"
+	BulkItemRejected BulkItemStatus = "rejected"
+)
+
+// BulkCreateOptions configures BulkCreate.
+type BulkCreateOptions struct {
+	// BatchSize is the number of indicators sent per request. Default: 500.
+	BatchSize int
+
+	// Concurrency is the maximum number of batches in flight. Default: 4.
+	Concurrency int
+}
+
+// BulkItemResult reports the outcome for one input indicator.
+type BulkItemResult struct {
+	// Index is the position of the indicator in the input sequence.
+	Index int
+
+	// Value is the indicator value as submitted.
+	Value string
+
+	Status BulkItemStatus
+
+	// Indicator is the indicator as stored by the server.
+	// It is nil for rejected items.
+	Indicator *Indicator
+
+	// Reason explains why a rejected item was not stored.
+	Reason string
+}
+
+// BulkCreateReport summarizes a bulk indicator ingestion.
+type BulkCreateReport struct {
+	// Results holds one entry per input indicator, ordered by Index. After
+	// a fatal error it covers only the indicators read before the run stopped.
+	Results []BulkItemResult
+
+	Created  int
+	Updated  int
+	Rejected int
+}
+
+// IncidentEventType classifies a change reported by Incidents.Watch.
+type IncidentEventType string
+
+const (
+	IncidentCreated  IncidentEventType = "created"
+	IncidentModified IncidentEventType = "modified"
+	IncidentClosed   IncidentEventType = "closed"
+)
+
+// IncidentEvent is a change to an incident observed by Incidents.Watch.
+type IncidentEvent struct {
+	Type     IncidentEventType
+	Incident *Incident
+}
+
+// WatchCheckpoint is the resumable state of Incidents.Watch. It is
+// JSON-serializable so that a CheckpointStore can persist it as-is.
+type WatchCheckpoint struct {
+	// Watermark is the latest Modified time delivered.
+	Watermark time.Time `json:"watermark"`
+
+	// Seen maps incident IDs to the Modified time last delivered, for
+	// incidents inside the clock-skew window. Watch uses it to suppress
+	// duplicates when overlapping polls return the same change.
+	Seen map[string]time.Time `json:"seen,omitempty"`
+}
+
+// IncidentSelection selects the incidents a batch operation applies to:
+// either an explicit list of IDs or every incident matching a filter.
+// Use IncidentIDs or IncidentsMatching to build one.
+type IncidentSelection struct {
+	IDs    []string
+	Filter *IncidentFilter
+}
+
+// IncidentIDs selects incidents by ID.
+func IncidentIDs(ids ...string) IncidentSelection {
+	return IncidentSelection{IDs: ids}
+}
+
+// IncidentsMatching selects every incident matching the filter.
+func IncidentsMatching(filter *IncidentFilter) IncidentSelection {
+	return IncidentSelection{Filter: filter}
+}
+
+// BatchItemStatus is the outcome of a batch operation for one incident.
+type BatchItemStatus string
+
+const (
+	BatchItemSucceeded BatchItemStatus = "succeeded"
+	BatchItemFailed    BatchItemStatus = "failed"
+)
+
+// BatchItemResult reports the outcome of a batch operation for one incident.
+type BatchItemResult struct {
+	ID     string
+	Status BatchItemStatus
+
+	// Incident is the incident as returned by the server, if any.
+	Incident *Incident
+
+	// Reason explains why a failed item was not processed.
+	Reason string
+}
+
+// BatchReport summarizes a batch incident operation.
+type BatchReport struct {
+	// Results holds one entry per requested ID, in request order. For
+	// filter-based operations it holds the incidents reported by the server.
+	Results []BatchItemResult
+
+	Succeeded int
+
+	// Failed counts incidents that were not processed. For filter-based
+	// operations it is the count reported by the server.
+	Failed int
+}
+
+// batchRequest is the request format of the incident batch endpoints.
+type batchRequest struct {
+	IDs    []string        `json:"ids,omitempty"`
+	Filter *IncidentFilter `json:"filter,omitempty"`
+	All    bool            `json:"all,omitempty"`
+
+	// Batch close fields.
+	CloseReason string    `json:"closeReason,omitempty"`
+	CloseNotes  string    `json:"closeNotes,omitempty"`
+	CloseDate   time.Time `json:"closeDate,omitzero"`
+
+	// Batch update fields.
+	Data         map[string]any `json:"data,omitempty"`
+	CustomFields map[string]any `json:"customFields,omitempty"`
+}
+
+// batchResponse is the response format of the incident batch endpoints.
+type batchResponse struct {
+	Data       []*Incident `json:"data"`
+	Total      int         `json:"total"`
+	NotUpdated int         `json:"notUpdated"`
+}
+
+// TaskState represents the state of a playbook task.
+type TaskState string
+
+const (
+	TaskStateNotStarted TaskState = ""
+	TaskStateInProgress TaskState = "inprogress"
+	TaskStateWaiting    TaskState = "Waiting"
+	TaskStateCompleted  TaskState = "Completed"
+	TaskStateError      TaskState = "Error"
+	TaskStateSkipped    TaskState = "WillNotBeExecuted"
+)
+
+// TaskDefinition describes what a playbook task does.
+type TaskDefinition struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	ScriptID    string `json:"scriptId,omitempty"`
+	PlaybookID  string `json:"playbookId,omitempty"`
+}
+
+// Task is a playbook task in an investigation's work plan.
+type Task struct {
+	// ID identifies the task within the investigation.
+	ID string `json:"id"`
+
+	// Type is the task type, such as "regular", "condition", "playbook",
+	// "title", or "collection".
+	Type  string    `json:"type"`
+	State TaskState `json:"state"`
+
+	Definition TaskDefinition `json:"task"`
+
+	Assignee      string    `json:"assignee,omitempty"`
+	DueDate       time.Time `json:"dueDate,omitzero"`
+	StartDate     time.Time `json:"startDate,omitzero"`
+	CompletedBy   string    `json:"completedBy,omitempty"`
+	CompletedDate time.Time `json:"completedDate,omitzero"`
+
+	// ParentPlaybookID is the ID of the sub-playbook task that contains
+	// this task. It is empty for tasks of the top-level playbook.
+	ParentPlaybookID string `json:"parentPlaybookID,omitempty"`
+}
+
+// IsManual reports whether the task is a manual task waiting for a person
+// to complete it, such as an approval.
+func (t *Task) IsManual() bool {
+	if t.State != TaskStateWaiting || t.Definition.ScriptID != "" {
+		return false
+	}
+	return t.Type == "regular" || t.Type == "condition"
+}
+
+// TaskCompletion holds the answer and comment for completing a task.
+type TaskCompletion struct {
+	// Answer is the task input, such as the chosen branch of a
+	// conditional (manual) task.
+	Answer string
+
+	// Comment is recorded in the War Room with the completion.
+	Comment string
+}
+
+// workplanResponse is the response format of /investigation/{id}/workplan.
+type workplanResponse struct {
+	Playbook *workplanPlaybook `json:"invPlaybook"`
+}
+
+// workplanPlaybook is a playbook run, keyed by task ID.
+type workplanPlaybook struct {
+	Tasks map[string]*workplanTask `json:"tasks"`
+}
+
+// workplanTask is a work plan task that may contain a sub-playbook.
+type workplanTask struct {
+	Task
+	SubPlaybook *workplanPlaybook `json:"subPlaybook,omitempty"`
+}
+
+// PlaybookTask is a task in a playbook definition.
+type PlaybookTask struct {
+	// ID identifies the task within the playbook.
+	ID string `json:"id"`
+
+	// TaskID is the ID of the task definition.
+	TaskID string `json:"taskid,omitempty"`
+
+	Type       string         `json:"type"`
+	Definition TaskDefinition `json:"task"`
+
+	// NextTasks maps a branch label ("#none#" for unconditional flow, or a
+	// condition answer) to the IDs of the tasks that follow.
+	NextTasks map[string][]string `json:"nexttasks,omitempty"`
+
+	// ScriptArguments holds the arguments passed to the task's script.
+	ScriptArguments map[string]any `json:"scriptarguments,omitempty"`
+}
+
+// PlaybookInputValue is the value of a playbook input: either a simple
+// literal or a complex context expression.
+type PlaybookInputValue struct {
+	Simple  string         `json:"simple,omitempty"`
+	Complex map[string]any `json:"complex,omitempty"`
+}
+
+// PlaybookInput is an input of a playbook.
+type PlaybookInput struct {
+	Key         string              `json:"key"`
+	Value       *PlaybookInputValue `json:"value,omitempty"`
+	Required    bool                `json:"required"`
+	Description string              `json:"description,omitempty"`
+}
+
+// PlaybookOutput is a context path a playbook writes.
+type PlaybookOutput struct {
+	ContextPath string `json:"contextPath"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+// Playbook is an XSOAR playbook definition.
+type Playbook struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Version     int      `json:"version,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+
+	// StartTaskID is the ID of the first task in Tasks.
+	StartTaskID string `json:"startTaskId,omitempty"`
+
+	// Tasks maps task IDs to tasks.
+	Tasks map[string]*PlaybookTask `json:"tasks,omitempty"`
+
+	Inputs  []PlaybookInput  `json:"inputs,omitempty"`
+	Outputs []PlaybookOutput `json:"outputs,omitempty"`
+
+	// System is true for playbooks installed from content packs.
+	System   bool      `json:"system,omitempty"`
+	Hidden   bool      `json:"hidden,omitempty"`
+	Modified time.Time `json:"modified,omitzero"`
+}
+
+// PlaybookFilter defines search criteria for playbooks.
+type PlaybookFilter struct {
+	// Query is a Lucene-style query string, such as `name:"Phishing*"`.
+	Query string `json:"query,omitempty"`
+}
+
+// playbookSearchRequest is the request format of /playbook/search.
+type playbookSearchRequest struct {
+	*PlaybookFilter
+	Page int `json:"page"`
+	Size int `json:"size"`
+}
+
+// playbookSearchResponse is the response format of /playbook/search.
+type playbookSearchResponse struct {
+	Playbooks []*Playbook `json:"playbooks"`
+	Total     int         `json:"total"`
+}
+
+// ListType is the content type of an XSOAR list.
+type ListType string
+
+const (
+	ListTypePlainText ListType = "plain_text"
+	ListTypeJSON      ListType = "json"
+	ListTypeMarkdown  ListType = "markdown"
+	ListTypeHTML      ListType = "html"
+)
+
+// List is an XSOAR list: named content, such as an allow-list or
+// configuration, that playbooks and scripts can read.
+type List struct {
+	// ID is the list identifier. For lists it is the same as Name.
+	ID          string   `json:"id,omitempty"`
+	Name        string   `json:"name"`
+	Data        string   `json:"data"`
+	Type        ListType `json:"type,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+
+	// Version is incremented by the server on every change. Saving a list
+	// with a stale version fails with a ConflictError.
+	Version  int       `json:"version,omitempty"`
+	Modified time.Time `json:"modified,omitzero"`
+}
+
+// DecodeJSON decodes the list content as JSON into v.
+func (l *List) DecodeJSON(v any) error {
+	return json.Unmarshal([]byte(l.Data), v)
+}
+
+// Lines returns the non-empty lines of the list content, with surrounding
+// whitespace removed.
+func (l *List) Lines() []string {
+	var lines []string
+	for line := range strings.Lines(l.Data) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// CSV parses the list content as comma-separated values. Records may have
+// different numbers of fields.
+func (l *List) CSV() ([][]string, error) {
+	r := csv.NewReader(strings.NewReader(l.Data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	return r.ReadAll()
+}
+
+// ScriptType is the language of an automation script.
+type ScriptType string
+
+const (
+	ScriptTypePython     ScriptType = "python"
+	ScriptTypePowerShell ScriptType = "powershell"
+	ScriptTypeJavaScript ScriptType = "javascript"
+)
+
+// ScriptArgument is an argument accepted by an automation script.
+type ScriptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+
+	// Default marks the argument that receives an unnamed value.
+	Default      bool     `json:"default,omitempty"`
+	DefaultValue string   `json:"defaultValue,omitempty"`
+	IsArray      bool     `json:"isArray,omitempty"`
+	Secret       bool     `json:"secret,omitempty"`
+	Predefined   []string `json:"predefined,omitempty"`
+}
+
+// ScriptOutput is a context path an automation script writes.
+type ScriptOutput struct {
+	ContextPath string `json:"contextPath"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+// Script is an XSOAR automation.
+type Script struct {
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name"`
+	Comment     string     `json:"comment,omitempty"`
+	Type        ScriptType `json:"type"`
+	Tags        []string   `json:"tags,omitempty"`
+	Version     int        `json:"version,omitempty"`
+	Modified    time.Time  `json:"modified,omitzero"`
+	System      bool       `json:"system,omitempty"`
+	Enabled     bool       `json:"enabled,omitempty"`
+	RunAs       string     `json:"runAs,omitempty"`
+	Subtype     string     `json:"subtype,omitempty"` // e.g. "python3"
+	DockerImage string     `json:"dockerImage,omitempty"`
+
+	// Code is the script source. Search results do not include it; use
+	// ScriptService.Get to load it.
+	Code string `json:"script,omitempty"`
+
+	Arguments []ScriptArgument `json:"arguments,omitempty"`
+	Outputs   []ScriptOutput   `json:"outputs,omitempty"`
+}
+
+// ScriptFilter defines search criteria for automation scripts.
+type ScriptFilter struct {
+	// Query is a Lucene-style query string, such as `tags:"enrichment"`.
+	Query string `json:"query,omitempty"`
+}
+
+// scriptSearchRequest is the request format of /automation/search.
+type scriptSearchRequest struct {
+	*ScriptFilter
+	Page int `json:"page"`
+	Size int `json:"size"`
+}
+
+// scriptSearchResponse is the response format of /automation/search.
+type scriptSearchResponse struct {
+	Scripts []*Script `json:"scripts"`
+	Total   int       `json:"total"`
+}
+
+// ParamType is the type of an integration configuration parameter.
+type ParamType int
+
+const (
+	ParamTypeShortText    ParamType = 0
+	ParamTypeEncrypted    ParamType = 4
+	ParamTypeBoolean      ParamType = 8
+	ParamTypeCredentials  ParamType = 9
+	ParamTypeLongText     ParamType = 12
+	ParamTypeIncidentType ParamType = 13
+	ParamTypeSingleSelect ParamType = 15
+	ParamTypeMultiSelect  ParamType = 16
+)
+
+// CredentialsParam is the value of a ParamTypeCredentials parameter. Set
+// Credential instead of Identifier and Password to reference a credential
+// stored in XSOAR by name.
+type CredentialsParam struct {
+	Identifier string `json:"identifier,omitempty"`
+	Password   string `json:"password,omitempty"`
+	Credential string `json:"credential,omitempty"`
+}
+
+// IntegrationParam defines a configuration parameter of an integration.
+type IntegrationParam struct {
+	Name         string    `json:"name"`
+	Display      string    `json:"display,omitempty"`
+	Type         ParamType `json:"type"`
+	Required     bool      `json:"required,omitempty"`
+	DefaultValue string    `json:"defaultValue,omitempty"`
+	Options      []string  `json:"options,omitempty"`
+	Hidden       bool      `json:"hidden,omitempty"`
+}
+
+// IntegrationBrand is an installed integration that instances can be
+// configured from.
+type IntegrationBrand struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Display     string `json:"display,omitempty"`
+	Category    string `json:"category,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Configuration defines the parameters an instance must provide.
+	Configuration []IntegrationParam `json:"configuration,omitempty"`
+}
+
+// NewInstance returns an unsaved, enabled instance of the brand with its
+// parameters set to their default values.
+func (b *IntegrationBrand) NewInstance(name string) *IntegrationInstance {
+	instance := &IntegrationInstance{
+		Name:          name,
+		Brand:         b.Name,
+		Category:      b.Category,
+		Enabled:       true,
+		Configuration: b,
+	}
+	for _, param := range b.Configuration {
+		p := InstanceParam{
+			Name:     param.Name,
+			Display:  param.Display,
+			Type:     param.Type,
+			Required: param.Required,
+			Options:  param.Options,
+		}
+		if param.DefaultValue != "" {
+			p.Value = decodeParamValue(param.Type, param.DefaultValue)
+			p.HasValue = true
+		}
+		instance.Data = append(instance.Data, p)
+	}
+	return instance
+}
+
+// InstanceParam is a parameter value of an integration instance.
+//
+// Value holds a bool for ParamTypeBoolean, a CredentialsParam for
+// ParamTypeCredentials, a []string for ParamTypeMultiSelect, and a string
+// for all other types.
+type InstanceParam struct {
+	Name     string    `json:"name"`
+	Display  string    `json:"display,omitempty"`
+	Type     ParamType `json:"type"`
+	Value    any       `json:"value"`
+	HasValue bool      `json:"hasvalue"`
+	Required bool      `json:"required,omitempty"`
+	Options  []string  `json:"options,omitempty"`
+}
+
+// instanceParamJSON has the fields of InstanceParam without its JSON methods.
+type instanceParamJSON InstanceParam
+
+// UnmarshalJSON implements json.Unmarshaler. Values are decoded into the
+// Go type documented for the parameter type.
+func (p *InstanceParam) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		instanceParamJSON
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = InstanceParam(raw.instanceParamJSON)
+	if len(raw.Value) == 0 || string(raw.Value) == "null" {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(raw.Value, &value); err != nil {
+		return err
+	}
+	switch p.Type {
+	case ParamTypeCredentials:
+		var creds CredentialsParam
+		if _, ok := value.(map[string]any); ok {
+			if err := json.Unmarshal(raw.Value, &creds); err != nil {
+				return err
+			}
+			p.Value = creds
+			return nil
+		}
+	case ParamTypeMultiSelect:
+		var values []string
+		if _, ok := value.([]any); ok {
+			if err := json.Unmarshal(raw.Value, &values); err != nil {
+				return err
+			}
+			p.Value = values
+			return nil
+		}
+	}
+	if s, ok := value.(string); ok {
+		p.Value = decodeParamValue(p.Type, s)
+		return nil
+	}
+	p.Value = value
+	return nil
+}
+
+// decodeParamValue converts a string value, as XSOAR stores defaults and
+// some values, into the Go type for the parameter type.
+func decodeParamValue(paramType ParamType, value string) any {
+	switch paramType {
+	case ParamTypeBoolean:
+		return value == "true"
+	case ParamTypeMultiSelect:
+		if value == "" {
+			return []string{}
+		}
+		return strings.Split(value, ",")
+	default:
+		return value
+	}
+}
+
+// validParamValue reports whether value has the Go type for the parameter type.
+func validParamValue(paramType ParamType, value any) bool {
+	switch paramType {
+	case ParamTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case ParamTypeCredentials:
+		_, ok := value.(CredentialsParam)
+		return ok
+	case ParamTypeMultiSelect:
+		_, ok := value.([]string)
+		return ok
+	default:
+		_, ok := value.(string)
+		return ok
+	}
+}
+
+// IntegrationInstance is a configured instance of an integration brand.
+type IntegrationInstance struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Brand    string `json:"brand"`
+	Category string `json:"category,omitempty"`
+	Enabled  bool   `json:"-"`
+
+	// Engine is the ID of the engine the instance runs on, if any.
+	Engine string `json:"engine,omitempty"`
+
+	// Data holds the instance's parameter values.
+	Data []InstanceParam `json:"data"`
+
+	// Configuration is the brand definition. The API requires it when
+	// saving an instance; instances returned by Instances and GetInstance
+	// have it attached, and IntegrationBrand.NewInstance sets it.
+	Configuration *IntegrationBrand `json:"configuration,omitempty"`
+
+	Version int `json:"version,omitempty"`
+}
+
+// integrationInstanceJSON has the fields of IntegrationInstance without its JSON methods.
+type integrationInstanceJSON IntegrationInstance
+
+// instanceWire is the wire format of an instance; the API encodes the
+// enabled flag as the string "true" or "false".
+type instanceWire struct {
+	integrationInstanceJSON
+	Enabled string `json:"enabled"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (i IntegrationInstance) MarshalJSON() ([]byte, error) {
+	return json.Marshal(instanceWire{
+		integrationInstanceJSON: integrationInstanceJSON(i),
+		Enabled:                 strconv.FormatBool(i.Enabled),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (i *IntegrationInstance) UnmarshalJSON(data []byte) error {
+	var wire instanceWire
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	*i = IntegrationInstance(wire.integrationInstanceJSON)
+	i.Enabled = wire.Enabled == "true"
+	return nil
+}
+
+// Param returns the parameter with the given name.
+func (i *IntegrationInstance) Param(name string) (*InstanceParam, bool) {
+	for idx := range i.Data {
+		if i.Data[idx].Name == name {
+			return &i.Data[idx], true
+		}
+	}
+	return nil, false
+}
+
+// SetParam sets the value of a parameter. The value must have the Go type
+// documented on InstanceParam for the parameter's type, and for select
+// parameters be one of its options.
+func (i *IntegrationInstance) SetParam(name string, value any) error {
+	param, ok := i.Param(name)
+	if !ok {
+		return &ValidationError{
+			APIError: APIError{Message: "unknown parameter"},
+			Fields:   map[string]string{name: "not defined by the integration"},
+		}
+	}
+	if !validParamValue(param.Type, value) {
+		return &ValidationError{
+			APIError: APIError{Message: "invalid parameter value"},
+			Fields:   map[string]string{name: fmt.Sprintf("unexpected value type %T", value)},
+		}
+	}
+	if err := validateParamOptions(param, value); err != nil {
+		return err
+	}
+	param.Value = value
+	param.HasValue = true
+	return nil
+}
+
+// validateParamOptions checks select values against the parameter options.
+func validateParamOptions(param *InstanceParam, value any) error {
+	if len(param.Options) == 0 {
+		return nil
+	}
+	var values []string
+	switch v := value.(type) {
+	case string:
+		if param.Type == ParamTypeSingleSelect {
+			values = []string{v}
+		}
+	case []string:
+		values = v
+	}
+	for _, v := range values {
+		if !slices.Contains(param.Options, v) {
+			return &ValidationError{
+				APIError: APIError{Message: "invalid parameter value"},
+				Fields:   map[string]string{param.Name: fmt.Sprintf("%q is not one of %v", v, param.Options)},
+			}
+		}
+	}
+	return nil
+}
+
+// IntegrationTestResult is the outcome of an instance's test module.
+type IntegrationTestResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// integrationSearchRequest is the request format of /settings/integration/search.
+type integrationSearchRequest struct {
+	Page int `json:"page"`
+	Size int `json:"size"`
+}
+
+// integrationSearchResponse is the response format of /settings/integration/search.
+type integrationSearchResponse struct {
+	Brands    []*IntegrationBrand    `json:"configurations"`
+	Instances []*IntegrationInstance `json:"instances"`
+}
+
+// IncidentType is an incident type, which selects the playbook and layout
+// used for incidents of that type.
+type IncidentType struct {
+	ID                  string    `json:"id,omitempty"`
+	Name                string    `json:"name"`
+	Color               string    `json:"color,omitempty"`
+	Playbook            string    `json:"playbookId,omitempty"`
+	Layout              string    `json:"layout,omitempty"`
+	PreProcessingScript string    `json:"preProcessingScript,omitempty"`
+	ClosureScript       string    `json:"closureScript,omitempty"`
+	Disabled            bool      `json:"disabled,omitempty"`
+	System              bool      `json:"system,omitempty"`
+	Version             int       `json:"version,omitempty"`
+	Modified            time.Time `json:"modified,omitzero"`
+
+	// AutoRun starts the playbook when an incident of the type is created.
+	AutoRun bool `json:"autorun,omitempty"`
+}
+
+// FieldType is the value type of an incident field.
+type FieldType string
+
+const (
+	FieldTypeShortText    FieldType = "shortText"
+	FieldTypeLongText     FieldType = "longText"
+	FieldTypeMarkdown     FieldType = "markdown"
+	FieldTypeHTML         FieldType = "html"
+	FieldTypeURL          FieldType = "url"
+	FieldTypeNumber       FieldType = "number"
+	FieldTypeBoolean      FieldType = "boolean"
+	FieldTypeDate         FieldType = "date"
+	FieldTypeTimer        FieldType = "timer"
+	FieldTypeSingleSelect FieldType = "singleSelect"
+	FieldTypeMultiSelect  FieldType = "multiSelect"
+	FieldTypeTagsSelect   FieldType = "tagsSelect"
+	FieldTypeUser         FieldType = "user"
+	FieldTypeRole         FieldType = "role"
+	FieldTypeGrid         FieldType = "grid"
+	FieldTypeAttachments  FieldType = "attachments"
+)
+
+// IncidentField is the definition of an incident field. Custom fields are
+// set on incidents through CustomFields, keyed by CLIName.
+type IncidentField struct {
+	ID          string    `json:"id,omitempty"`
+	Name        string    `json:"name"`
+	CLIName     string    `json:"cliName"`
+	Type        FieldType `json:"type"`
+	Description string    `json:"description,omitempty"`
+	Group       int       `json:"group"` // 0 for incident fields
+	Content     bool      `json:"content,omitempty"`
+	System      bool      `json:"system,omitempty"`
+	Version     int       `json:"version,omitempty"`
+
+	// SelectValues are the allowed values of select fields. An empty
+	// first value, as returned by the server, is not included.
+	SelectValues []string `json:"selectValues,omitempty"`
+
+	// Required and Unsearchable apply to incidents of AssociatedTypes,
+	// or of every type if AssociatedToAll is set.
+	Required        bool     `json:"required,omitempty"`
+	Unsearchable    bool     `json:"unsearchable,omitempty"`
+	AssociatedTypes []string `json:"associatedTypes,omitempty"`
+	AssociatedToAll bool     `json:"associatedToAll,omitempty"`
+}
+
+// incidentFieldJSON is IncidentField without its JSON methods.
+type incidentFieldJSON IncidentField
+
+// UnmarshalJSON drops the empty placeholder the server includes in select
+// values.
+func (f *IncidentField) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*incidentFieldJSON)(f)); err != nil {
+		return err
+	}
+	f.SelectValues = slices.DeleteFunc(f.SelectValues, func(value string) bool {
+		return value == ""
+	})
+	return nil
+}
+
+// AppliesTo reports whether the field is associated with incidentType.
+func (f *IncidentField) AppliesTo(incidentType string) bool {
+	return f.AssociatedToAll || slices.Contains(f.AssociatedTypes, incidentType)
+}
+
+// Layout is an incident layout container. The tab and section contents are
+// kept as raw JSON, as their structure depends on the layout kind.
+type Layout struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Group       string `json:"group,omitempty"` // "incident" or "indicator"
+	Description string `json:"description,omitempty"`
+	System      bool   `json:"system,omitempty"`
+	Version     int    `json:"version,omitempty"`
+
+	DetailsV2 json.RawMessage `json:"detailsV2,omitempty"`
+	QuickView json.RawMessage `json:"quickView,omitempty"`
+	Edit      json.RawMessage `json:"edit,omitempty"`
+	Close     json.RawMessage `json:"close,omitempty"`
+	Mobile    json.RawMessage `json:"mobile,omitempty"`
 }
@@ -1,6 +1,7 @@
 package xsoar_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"testing"
 
@@ -149,6 +150,127 @@ func TestIncidentJSONSerialization(t *testing.T) {
 	})
 }
 
+func TestIncidentRawData(t *testing.T) {
+	const payload = `{
+		"id": "inc-1",
+		"name": "Phishing",
+		"severity": 2,
+		"CustomFields": {"priority": "urgent"},
+		"sla": 120,
+		"dbotMirrorId": 12345678901234567890,
+		"sourceBrand": "EWS v2",
+		"rawJSON": "{\"subject\":\"hi\"}",
+		"labels": [{"type": "Email", "value": "a@b.c"}]
+	}`
+
+	t.Run("unmarshal captures unmapped fields", func(t *testing.T) {
+		var incident xsoar.Incident
+		err := json.Unmarshal([]byte(payload), &incident)
+		require.NoError(t, err)
+
+		assert.Equal(t, "inc-1", incident.ID)
+		assert.Equal(t, xsoar.SeverityLow, incident.Severity)
+		assert.Len(t, incident.RawData, 4)
+		assert.Equal(t, json.Number("120"), incident.RawData["sla"])
+		assert.Equal(t, "EWS v2", incident.RawData["sourceBrand"])
+		assert.NotContains(t, incident.RawData, "id")
+		assert.NotContains(t, incident.RawData, "CustomFields")
+		assert.NotContains(t, incident.RawData, "labels")
+	})
+
+	t.Run("no unmapped fields leaves RawData nil", func(t *testing.T) {
+		var incident xsoar.Incident
+		err := json.Unmarshal([]byte(`{"id": "inc-1", "Name": "case-insensitive"}`), &incident)
+		require.NoError(t, err)
+
+		assert.Equal(t, "case-insensitive", incident.Name)
+		assert.Nil(t, incident.RawData)
+	})
+
+	t.Run("round-trips unmapped fields", func(t *testing.T) {
+		var incident xsoar.Incident
+		err := json.Unmarshal([]byte(payload), &incident)
+		require.NoError(t, err)
+
+		incident.Name = "Renamed"
+		data, err := json.Marshal(&incident)
+		require.NoError(t, err)
+
+		var result map[string]any
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		require.NoError(t, dec.Decode(&result))
+
+		assert.Equal(t, "Renamed", result["name"])
+		assert.Equal(t, json.Number("2"), result["severity"])
+		assert.Equal(t, json.Number("12345678901234567890"), result["dbotMirrorId"])
+		assert.Equal(t, `{"subject":"hi"}`, result["rawJSON"])
+	})
+
+	t.Run("round-trips unmapped fields of a value", func(t *testing.T) {
+		var incident xsoar.Incident
+		err := json.Unmarshal([]byte(`{"id": "1", "sla": 5, "dbotMirrorId": "x"}`), &incident)
+		require.NoError(t, err)
+
+		data, err := json.Marshal(incident)
+		require.NoError(t, err)
+
+		var result map[string]any
+		require.NoError(t, json.Unmarshal(data, &result))
+		assert.Equal(t, "1", result["id"])
+		assert.Equal(t, float64(5), result["sla"])
+		assert.Equal(t, "x", result["dbotMirrorId"])
+	})
+
+	t.Run("modeled fields take precedence", func(t *testing.T) {
+		incident := &xsoar.Incident{
+			ID:      "inc-1",
+			RawData: map[string]any{"id": "stale", "sla": 5},
+		}
+		data, err := json.Marshal(incident)
+		require.NoError(t, err)
+
+		var result map[string]any
+		require.NoError(t, json.Unmarshal(data, &result))
+		assert.Equal(t, "inc-1", result["id"])
+		assert.InDelta(t, float64(5), result["sla"], 0.001)
+	})
+}
+
+func TestIndicatorScore(t *testing.T) {
+	tests := []struct {
+		score    xsoar.IndicatorScore
+		expected string
+	}{
+		{xsoar.ScoreUnknown, "Unknown"},
+		{xsoar.ScoreGood, "Good"},
+		{xsoar.ScoreSuspicious, "Suspicious"},
+		{xsoar.ScoreBad, "Bad"},
+		{xsoar.IndicatorScore(42), "Unknown"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tt.score.String())
+	}
+}
+
+func TestIndicatorPage(t *testing.T) {
+	t.Run("HasMore true", func(t *testing.T) {
+		page := &xsoar.IndicatorPage{
+			Data:   make([]*xsoar.Indicator, 100),
+			Total:  250,
+			Offset: 100,
+		}
+		assert.True(t, page.HasMore())
+		assert.Equal(t, 200, page.NextOffset())
+	})
+
+	t.Run("HasMore false on empty page", func(t *testing.T) {
+		page := &xsoar.IndicatorPage{Total: 250, Offset: 300}
+		assert.False(t, page.HasMore())
+	})
+}
+
 func TestLabel(t *testing.T) {
 	label := xsoar.Label{Type: "category", Value: "malware"}
 
@@ -161,3 +283,111 @@ func TestLabel(t *testing.T) {
 
 	assert.Equal(t, label, result)
 }
+
+func TestListContent(t *testing.T) {
+	t.Run("DecodeJSON", func(t *testing.T) {
+		list := &xsoar.List{Data: `{"threshold": 5, "domains": ["example.com"]}`}
+
+		var config struct {
+			Threshold int      `json:"threshold"`
+			Domains   []string `json:"domains"`
+		}
+		require.NoError(t, list.DecodeJSON(&config))
+		assert.Equal(t, 5, config.Threshold)
+		assert.Equal(t, []string{"example.com"}, config.Domains)
+	})
+
+	t.Run("Lines skips blank lines and trims whitespace", func(t *testing.T) {
+		list := &xsoar.List{Data: "10.0.0.1\r\n  10.0.0.2 \n\n10.0.0.3"}
+		assert.Equal(t, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, list.Lines())
+		assert.Empty(t, (&xsoar.List{}).Lines())
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		list := &xsoar.List{Data: "host, owner\nsrv-1, \"Doe, Jane\"\nsrv-2"}
+		records, err := list.CSV()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"host", "owner"}, {"srv-1", "Doe, Jane"}, {"srv-2"}}, records)
+	})
+}
+
+func TestInstanceParam_UnmarshalJSON(t *testing.T) {
+	var instance xsoar.IntegrationInstance
+	err := json.Unmarshal([]byte(`{
+		"id": "i-1", "name": "VT", "brand": "VirusTotal", "enabled": "true",
+		"data": [
+			{"name": "url", "type": 0, "value": "https://vt.example.com", "hasvalue": true},
+			{"name": "insecure", "type": 8, "value": "false", "hasvalue": true},
+			{"name": "proxy", "type": 8, "value": true, "hasvalue": true},
+			{"name": "credentials", "type": 9, "value": {"identifier": "svc", "password": "secret"}, "hasvalue": true},
+			{"name": "feeds", "type": 16, "value": ["a", "b"], "hasvalue": true},
+			{"name": "unset", "type": 0, "value": null}
+		]
+	}`), &instance)
+	require.NoError(t, err)
+
+	assert.True(t, instance.Enabled)
+	require.Len(t, instance.Data, 6)
+	assert.Equal(t, "https://vt.example.com", instance.Data[0].Value)
+	assert.Equal(t, false, instance.Data[1].Value)
+	assert.Equal(t, true, instance.Data[2].Value)
+	assert.Equal(t, xsoar.CredentialsParam{Identifier: "svc", Password: "secret"}, instance.Data[3].Value)
+	assert.Equal(t, []string{"a", "b"}, instance.Data[4].Value)
+	assert.Nil(t, instance.Data[5].Value)
+
+	data, err := json.Marshal(&instance)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"enabled":"true"`)
+}
+
+func TestIntegrationInstance_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(xsoar.IntegrationInstance{Name: "VT_prod", Brand: "VirusTotal", Enabled: true})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"enabled":"true"`)
+
+	data, err = json.Marshal([]xsoar.IntegrationInstance{{Name: "VT_test", Brand: "VirusTotal"}})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"enabled":"false"`)
+}
+
+func TestIntegrationInstance_SetParam(t *testing.T) {
+	brand := &xsoar.IntegrationBrand{
+		Name: "VirusTotal",
+		Configuration: []xsoar.IntegrationParam{
+			{Name: "url", Type: xsoar.ParamTypeShortText, DefaultValue: "https://vt.example.com"},
+			{Name: "insecure", Type: xsoar.ParamTypeBoolean, DefaultValue: "false"},
+			{Name: "credentials", Type: xsoar.ParamTypeCredentials, Required: true},
+			{Name: "region", Type: xsoar.ParamTypeSingleSelect, Options: []string{"eu", "us"}},
+		},
+	}
+	instance := brand.NewInstance("VT_prod")
+
+	assert.Equal(t, "VirusTotal", instance.Brand)
+	assert.True(t, instance.Enabled)
+	param, ok := instance.Param("insecure")
+	require.True(t, ok)
+	assert.Equal(t, false, param.Value)
+	assert.True(t, param.HasValue)
+
+	require.NoError(t, instance.SetParam("credentials", xsoar.CredentialsParam{Identifier: "svc", Password: "new"}))
+	require.NoError(t, instance.SetParam("region", "eu"))
+
+	tests := []struct {
+		name  string
+		param string
+		value any
+	}{
+		{"unknown parameter", "missing", "x"},
+		{"wrong type", "insecure", "true"},
+		{"credentials as string", "credentials", "secret"},
+		{"option not allowed", "region", "apac"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := instance.SetParam(tt.param, tt.value)
+			var validationErr *xsoar.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Contains(t, validationErr.Fields, tt.param)
+		})
+	}
+}
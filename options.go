@@ -9,12 +9,17 @@ import (
 type ClientOption func(*clientConfig)
 
 type clientConfig struct {
-	baseURL    string
-	keyID      string
-	apiKey     string
-	httpClient *http.Client
-	timeout    time.Duration
-	userAgent  string
+	baseURL       string
+	authenticator Authenticator
+	httpClient    *http.Client
+	timeout       time.Duration
+	userAgent     string
+	retry         *RetryPolicy
+	maxBodySize   int64
+
+	// Custom field validation settings.
+	fieldValidation bool
+	fieldCacheTTL   time.Duration
 }
 
 // WithBaseURL sets the XSOAR API base URL.
@@ -24,11 +29,28 @@ func WithBaseURL(url string) ClientOption {
 	}
 }
 
-// WithAPIKey sets the XSOAR 8.x API credentials.
+// WithAPIKey sets the XSOAR 8.x standard API key credentials.
 func WithAPIKey(keyID, apiKey string) ClientOption {
+	return WithAuthenticator(NewStandardKeyAuthenticator(keyID, apiKey))
+}
+
+// WithAdvancedAPIKey sets XSOAR 8.x advanced API key credentials.
+// Advanced keys never send the raw key; each request is signed with a
+// SHA-256 hash of the key, a fresh nonce, and the current timestamp.
+func WithAdvancedAPIKey(keyID, apiKey string) ClientOption {
+	return WithAuthenticator(NewAdvancedKeyAuthenticator(keyID, apiKey))
+}
+
+// WithLegacyAPIKey sets XSOAR 6.x API key credentials.
+func WithLegacyAPIKey(apiKey string) ClientOption {
+	return WithAuthenticator(NewLegacyKeyAuthenticator(apiKey))
+}
+
+// WithAuthenticator sets a custom Authenticator, replacing any API key
+// configured with WithAPIKey, WithAdvancedAPIKey, or WithLegacyAPIKey.
+func WithAuthenticator(a Authenticator) ClientOption {
 	return func(c *clientConfig) {
-		c.keyID = keyID
-		c.apiKey = apiKey
+		c.authenticator = a
 	}
 }
 
@@ -55,11 +77,53 @@ func WithUserAgent(ua string) ClientOption {
 	}
 }
 
+// WithRetryPolicy enables automatic retries of failed requests.
+// Retries are disabled by default.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *clientConfig) {
+		c.retry = &policy
+	}
+}
+
+// WithMaxResponseSize sets the maximum size in bytes of a buffered API
+// response. Larger responses fail with an error instead of exhausting
+// memory. Streaming downloads such as DownloadAttachment are not limited.
+// Default: 10MB.
+func WithMaxResponseSize(n int64) ClientOption {
+	return func(c *clientConfig) {
+		c.maxBodySize = n
+	}
+}
+
+// WithCustomFieldValidation enables client-side validation of CustomFields
+// in Incidents.Create, Update, BatchUpdate and UpdateWithRetry; for
+// UpdateWithRetry, the fields added or changed by mutate are checked.
+// Incident field definitions are loaded from the server on first use and
+// cached for ttl (default 15 minutes if ttl <= 0). Requests with unknown
+// field names, values of the wrong type, or values outside a select
+// field's options fail with a ValidationError naming each offending field,
+// without being sent.
+func WithCustomFieldValidation(ttl time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.fieldValidation = true
+		c.fieldCacheTTL = ttl
+	}
+}
+
 // RequestOption configures individual API requests.
 type RequestOption func(*requestConfig)
 
 type requestConfig struct {
-	headers http.Header
+	headers    http.Header
+	idempotent bool
+	fields     []string
+	consistent bool
+	pageSize   int
+	prefetch   bool
+
+	// Incidents.Watch settings.
+	clockSkew   time.Duration
+	checkpoints CheckpointStore
 }
 
 func newRequestConfig() *requestConfig {
@@ -74,6 +138,14 @@ func (r *requestConfig) apply(opts ...RequestOption) {
 	}
 }
 
+// pageLimit returns the page size for iterators, capped at maxPageSize.
+func (r *requestConfig) pageLimit() int {
+	if r.pageSize <= 0 {
+		return defaultPageSize
+	}
+	return min(r.pageSize, maxPageSize)
+}
+
 // WithHeader adds a custom header to a request.
 func WithHeader(key, value string) RequestOption {
 	return func(r *requestConfig) {
@@ -94,3 +166,68 @@ func WithHeaders(headers map[string]string) RequestOption {
 func WithRequestID(id string) RequestOption {
 	return WithHeader("X-Request-ID", id)
 }
+
+// WithIdempotent marks a mutating request as safe to retry.
+// Use it when repeating the call has no additional effect, for example
+// an update that sets fields to fixed values.
+func WithIdempotent() RequestOption {
+	return func(r *requestConfig) {
+		r.idempotent = true
+	}
+}
+
+// WithFields limits search results to the given fields, reducing response
+// size for large exports. Fields not requested are left at their zero value.
+// The incident ID is always returned. Applies to search operations only.
+func WithFields(fields ...string) RequestOption {
+	return func(r *requestConfig) {
+		r.fields = append(r.fields, fields...)
+	}
+}
+
+// WithConsistentIteration makes Search page by creation time and incident ID
+// instead of by offset. Incidents created or closed while iterating cannot
+// cause other incidents to be skipped or returned twice. Results are ordered
+// by creation time; the filter's Sort is ignored. Applies to Search only.
+func WithConsistentIteration() RequestOption {
+	return func(r *requestConfig) {
+		r.consistent = true
+	}
+}
+
+// WithPageSize sets the number of items iterators fetch per request.
+// Values above the API maximum of 1000 are capped; zero or negative values
+// use the default of 100. Applies to Search and List iterators.
+func WithPageSize(size int) RequestOption {
+	return func(r *requestConfig) {
+		r.pageSize = size
+	}
+}
+
+// WithPrefetch makes Incidents.Search fetch the next page in the background
+// while the current page is being consumed. Breaking out of the loop cancels
+// the in-flight request. Applies to Incidents.Search only.
+func WithPrefetch() RequestOption {
+	return func(r *requestConfig) {
+		r.prefetch = true
+	}
+}
+
+// WithCheckpoint makes Incidents.Watch load its starting point from store
+// and save progress after each poll, so that a restarted watcher resumes
+// where the previous one stopped. Applies to Incidents.Watch only.
+func WithCheckpoint(store CheckpointStore) RequestOption {
+	return func(r *requestConfig) {
+		r.checkpoints = store
+	}
+}
+
+// WithClockSkew sets how far back Incidents.Watch re-reads before its
+// watermark to catch changes whose Modified time lags behind, for example
+// due to clock differences between XSOAR nodes. Changes seen twice are
+// delivered once. Default: 30s. Applies to Incidents.Watch only.
+func WithClockSkew(skew time.Duration) RequestOption {
+	return func(r *requestConfig) {
+		r.clockSkew = skew
+	}
+}
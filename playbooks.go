@@ -0,0 +1,212 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// PlaybookService provides operations on playbook definitions.
+//
+//go:generate mockery --name=PlaybookService --output=mocks --outpkg=mocks --filename=playbook_service.go
+type PlaybookService interface {
+	// Search returns an iterator over all playbooks matching the filter.
+	// A nil filter matches all playbooks.
+	Search(ctx context.Context, filter *PlaybookFilter, opts ...RequestOption) iter.Seq2[*Playbook, error]
+
+	// Get retrieves a playbook by ID.
+	Get(ctx context.Context, id string, opts ...RequestOption) (*Playbook, error)
+
+	// Export streams a playbook as YAML, in the format used by content
+	// packs. The caller must close the returned reader.
+	Export(ctx context.Context, id string, opts ...RequestOption) (io.ReadCloser, error)
+
+	// Import uploads a playbook YAML file, creating or replacing the
+	// playbook with the ID it declares. The content is streamed without
+	// being buffered in memory.
+	Import(ctx context.Context, filename string, content io.Reader, opts ...RequestOption) (*Playbook, error)
+
+	// Save creates or updates a playbook from its JSON definition.
+	Save(ctx context.Context, playbook *Playbook, opts ...RequestOption) (*Playbook, error)
+
+	// Delete removes a playbook by ID.
+	Delete(ctx context.Context, id string, opts ...RequestOption) error
+}
+
+// playbookService implements PlaybookService.
+type playbookService struct {
+	transport *api.Transport
+}
+
+func newPlaybookService(transport *api.Transport) *playbookService {
+	return &playbookService{transport: transport}
+}
+
+// Search returns an iterator over all playbooks matching the filter.
+func (s *playbookService) Search(ctx context.Context, filter *PlaybookFilter, opts ...RequestOption) iter.Seq2[*Playbook, error] {
+	return func(yield func(*Playbook, error) bool) {
+		reqCfg := newRequestConfig()
+		reqCfg.apply(opts...)
+
+		body := &playbookSearchRequest{
+			PlaybookFilter: filter,
+			Size:           reqCfg.pageLimit(),
+		}
+		seen := 0
+
+		for {
+			var result playbookSearchResponse
+			err := doRequest(ctx, s.transport, &api.Request{
+				Method:     http.MethodPost,
+				Path:       "/playbook/search",
+				Body:       body,
+				Headers:    reqCfg.headers,
+				Idempotent: true,
+			}, &result)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !yieldItems(ctx, result.Playbooks, yield) {
+				return
+			}
+
+			seen += len(result.Playbooks)
+			if len(result.Playbooks) < body.Size || seen >= result.Total {
+				return
+			}
+			body.Page++
+		}
+	}
+}
+
+// Get retrieves a playbook by ID.
+func (s *playbookService) Get(ctx context.Context, id string, opts ...RequestOption) (*Playbook, error) {
+	if err := validateRequired("playbook ID", id); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result Playbook
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodGet,
+		Path:       fmt.Sprintf("/playbook/%s", url.PathEscape(id)),
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, &result)
+	if err != nil {
+		return nil, withResource(err, "playbook", id)
+	}
+
+	return &result, nil
+}
+
+// Export streams a playbook as YAML.
+func (s *playbookService) Export(ctx context.Context, id string, opts ...RequestOption) (io.ReadCloser, error) {
+	if err := validateRequired("playbook ID", id); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	headers := reqCfg.headers.Clone()
+	if headers.Get("Accept") == "" {
+		headers.Set("Accept", "*/*")
+	}
+
+	body, err := doStream(ctx, s.transport, &api.Request{
+		Method:  http.MethodGet,
+		Path:    fmt.Sprintf("/playbook/%s/yaml", url.PathEscape(id)),
+		Headers: headers,
+	})
+	if err != nil {
+		return nil, withResource(err, "playbook", id)
+	}
+
+	return body, nil
+}
+
+// Import uploads a playbook YAML file.
+func (s *playbookService) Import(ctx context.Context, filename string, content io.Reader, opts ...RequestOption) (*Playbook, error) {
+	if err := validateRequired("filename", filename); err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, &ValidationError{APIError: APIError{Message: "content cannot be nil"}}
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	body, contentType := multipartBody(nil, &formFile{field: "file", filename: filename, content: content})
+	defer func() { _ = body.Close() }()
+
+	var result Playbook
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:      http.MethodPost,
+		Path:        "/playbook/save/yaml",
+		RawBody:     body,
+		ContentType: contentType,
+		Headers:     reqCfg.headers,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Save creates or updates a playbook.
+func (s *playbookService) Save(ctx context.Context, playbook *Playbook, opts ...RequestOption) (*Playbook, error) {
+	if playbook == nil {
+		return nil, &ValidationError{APIError: APIError{Message: "playbook cannot be nil"}}
+	}
+	if err := validateRequired("playbook name", playbook.Name); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result Playbook
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/playbook/save",
+		Body:       playbook,
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Delete removes a playbook by ID.
+func (s *playbookService) Delete(ctx context.Context, id string, opts ...RequestOption) error {
+	if err := validateRequired("playbook ID", id); err != nil {
+		return err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/playbook/delete",
+		Body:       map[string]any{"id": id},
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, nil)
+	return withResource(err, "playbook", id)
+}
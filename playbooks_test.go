@@ -0,0 +1,192 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+func TestPlaybookService_Search(t *testing.T) {
+	t.Run("iterates all pages", func(t *testing.T) {
+		var pages []float64
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/playbook/search", r.URL.Path)
+
+			var reqBody map[string]any
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, "name:Phishing*", reqBody["query"])
+
+			page, ok := reqBody["page"].(float64)
+			assert.True(t, ok)
+			pages = append(pages, page)
+
+			var playbooks []*xsoar.Playbook
+			for i := range 2 {
+				if n := int(page)*2 + i; n < 3 {
+					playbooks = append(playbooks, &xsoar.Playbook{ID: fmt.Sprintf("pb-%d", n)})
+				}
+			}
+			err = json.NewEncoder(w).Encode(map[string]any{"playbooks": playbooks, "total": 3})
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		playbooks, err := xsoar.Collect(client.Playbooks.Search(ctx,
+			&xsoar.PlaybookFilter{Query: "name:Phishing*"}, xsoar.WithPageSize(2)))
+		require.NoError(t, err)
+
+		assert.Len(t, playbooks, 3)
+		assert.Equal(t, []float64{0, 1}, pages)
+	})
+}
+
+func TestPlaybookService_Get(t *testing.T) {
+	t.Run("decodes tasks, inputs and outputs", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/playbook/Phishing%20Triage", r.URL.EscapedPath())
+
+			_, err := w.Write([]byte(`{
+				"id": "Phishing Triage", "name": "Phishing Triage", "version": 3,
+				"startTaskId": "0",
+				"tasks": {
+					"0": {"id": "0", "type": "start", "task": {"name": ""}, "nexttasks": {"#none#": ["1"]}},
+					"1": {"id": "1", "taskid": "abc", "type": "regular",
+						"task": {"name": "Extract", "scriptId": "ExtractIndicators"},
+						"scriptarguments": {"text": {"simple": "${incident.details}"}}}
+				},
+				"inputs": [{"key": "Mailbox", "value": {"simple": "soc@example.com"}, "required": true}],
+				"outputs": [{"contextPath": "Email.From", "type": "string"}]
+			}`))
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		playbook, err := client.Playbooks.Get(ctx, "Phishing Triage")
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, playbook.Version)
+		require.Len(t, playbook.Tasks, 2)
+		assert.Equal(t, []string{"1"}, playbook.Tasks["0"].NextTasks["#none#"])
+		assert.Equal(t, "ExtractIndicators", playbook.Tasks["1"].Definition.ScriptID)
+		require.Len(t, playbook.Inputs, 1)
+		assert.Equal(t, "soc@example.com", playbook.Inputs[0].Value.Simple)
+		assert.True(t, playbook.Inputs[0].Required)
+		assert.Equal(t, "Email.From", playbook.Outputs[0].ContextPath)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		ctx := context.Background()
+		_, err := client.Playbooks.Get(ctx, "missing")
+		var notFoundErr *xsoar.NotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "playbook", notFoundErr.ResourceType)
+	})
+}
+
+func TestPlaybookService_Export(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/playbook/pb-1/yaml", r.URL.Path)
+		assert.Equal(t, "*/*", r.Header.Get("Accept"))
+		_, err := w.Write([]byte("id: pb-1\nname: Triage\n"))
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+	body, err := client.Playbooks.Export(ctx, "pb-1")
+	require.NoError(t, err)
+	defer func() { _ = body.Close() }()
+
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "id: pb-1\nname: Triage\n", string(data))
+}
+
+func TestPlaybookService_Import(t *testing.T) {
+	t.Run("uploads YAML file", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/playbook/save/yaml", r.URL.Path)
+
+			file, header, err := r.FormFile("file")
+			assert.NoError(t, err)
+			if err == nil {
+				defer func() { _ = file.Close() }()
+				data, err := io.ReadAll(file)
+				assert.NoError(t, err)
+				assert.Equal(t, "id: pb-1\n", string(data))
+				assert.Equal(t, "playbook-pb-1.yml", header.Filename)
+			}
+
+			err = json.NewEncoder(w).Encode(xsoar.Playbook{ID: "pb-1", Version: 2})
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		playbook, err := client.Playbooks.Import(ctx, "playbook-pb-1.yml", strings.NewReader("id: pb-1\n"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, playbook.Version)
+	})
+
+	t.Run("requires content", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call without content")
+		})
+
+		ctx := context.Background()
+		_, err := client.Playbooks.Import(ctx, "playbook.yml", nil)
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestPlaybookService_Save(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/playbook/save", r.URL.Path)
+
+		var playbook xsoar.Playbook
+		err := json.NewDecoder(r.Body).Decode(&playbook)
+		assert.NoError(t, err)
+		assert.Equal(t, "Triage", playbook.Name)
+
+		playbook.ID = "pb-new"
+		err = json.NewEncoder(w).Encode(playbook)
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+	playbook, err := client.Playbooks.Save(ctx, &xsoar.Playbook{Name: "Triage"})
+	require.NoError(t, err)
+	assert.Equal(t, "pb-new", playbook.ID)
+
+	_, err = client.Playbooks.Save(ctx, &xsoar.Playbook{})
+	var validationErr *xsoar.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func TestPlaybookService_Delete(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/playbook/delete", r.URL.Path)
+
+		var reqBody map[string]any
+		err := json.NewDecoder(r.Body).Decode(&reqBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "pb-1", reqBody["id"])
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ctx := context.Background()
+	err := client.Playbooks.Delete(ctx, "pb-1")
+	require.NoError(t, err)
+}
@@ -0,0 +1,294 @@
+// Package query builds XSOAR search query strings.
+//
+// XSOAR search endpoints accept a Lucene-style query language. Building
+// queries by string concatenation is error-prone: values with spaces must be
+// quoted, reserved characters must be escaped, and custom fields are
+// addressed by their machine name. This package renders correctly escaped
+// query syntax from typed terms:
+//
+//	q := query.And(
+//	    query.Field("status").Eq(xsoar.StatusActive),
+//	    query.Field("severity").GTE(3),
+//	    query.Field("owner").Eq("Jane Doe"),
+//	    query.CustomField("Affected Host").Wildcard("srv-*"),
+//	)
+//
+//	filter := &xsoar.IncidentFilter{Query: q.String()}
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Expr is a query expression.
+type Expr interface {
+	// String renders the expression in XSOAR query syntax.
+	String() string
+}
+
+// term is a single field:value expression.
+type term string
+
+func (t term) String() string { return string(t) }
+
+// group is a boolean combination of expressions.
+type group struct {
+	op    string
+	exprs []Expr
+}
+
+func (g group) String() string {
+	parts := make([]string, 0, len(g.exprs))
+	for _, e := range g.exprs {
+		if e == nil {
+			continue
+		}
+		s := e.String()
+		if s == "" {
+			continue
+		}
+		if inner, ok := e.(group); ok && inner.op != g.op && inner.size() > 1 {
+			s = "(" + s + ")"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " "+g.op+" ")
+}
+
+// size returns the number of non-empty expressions in the group.
+func (g group) size() int {
+	n := 0
+	for _, e := range g.exprs {
+		if e != nil && e.String() != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// nothing matches no documents. Every document has an ID, so it is
+// rendered as the negation of an ID existence check.
+type nothing struct{}
+
+func (nothing) String() string { return "-id:*" }
+
+// not negates an expression.
+type not struct {
+	expr Expr
+}
+
+func (n not) String() string {
+	if n.expr == nil {
+		return nothing{}.String()
+	}
+	s := n.expr.String()
+	switch s {
+	case "":
+		// An empty expression matches everything.
+		return nothing{}.String()
+	case nothing{}.String():
+		return Field("id").Exists().String()
+	}
+	if g, ok := n.expr.(group); ok && g.size() > 1 {
+		return "-(" + s + ")"
+	}
+	return "-" + s
+}
+
+// And matches documents matching all expressions. Empty expressions are skipped.
+func And(exprs ...Expr) Expr {
+	return group{op: "and", exprs: exprs}
+}
+
+// Or matches documents matching any expression. Empty expressions are skipped.
+func Or(exprs ...Expr) Expr {
+	return group{op: "or", exprs: exprs}
+}
+
+// Not matches documents that do not match the expression. Negating an
+// empty expression, which matches everything, matches nothing.
+func Not(expr Expr) Expr {
+	return not{expr: expr}
+}
+
+// Raw inserts a query fragment verbatim. The caller is responsible for escaping.
+func Raw(s string) Expr {
+	return term(s)
+}
+
+// FieldRef refers to a searchable field.
+type FieldRef struct {
+	name string
+}
+
+// Field refers to a field by its query name, such as "status" or "owner".
+// Reserved characters in the name are escaped; dots are kept so that
+// nested paths remain addressable.
+func Field(name string) FieldRef {
+	return FieldRef{name: name}
+}
+
+// CustomField refers to a custom field by its display name or machine name.
+// XSOAR addresses custom fields in queries by their machine (CLI) name, which
+// is the lower-cased name with all non-alphanumeric characters removed, so
+// CustomField("Affected Host") and CustomField("affected.host") both refer
+// to "affectedhost".
+func CustomField(name string) FieldRef {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return FieldRef{name: b.String()}
+}
+
+// Eq matches documents where the field equals value.
+func (f FieldRef) Eq(value any) Expr {
+	return f.term("", formatValue(value))
+}
+
+// In matches documents where the field equals any of the values. With no
+// values it matches nothing.
+func (f FieldRef) In(values ...any) Expr {
+	if len(values) == 0 {
+		return nothing{}
+	}
+	exprs := make([]Expr, 0, len(values))
+	for _, v := range values {
+		exprs = append(exprs, f.Eq(v))
+	}
+	return Or(exprs...)
+}
+
+// GT matches documents where the field is greater than value.
+func (f FieldRef) GT(value any) Expr {
+	return f.term(">", formatValue(value))
+}
+
+// GTE matches documents where the field is greater than or equal to value.
+func (f FieldRef) GTE(value any) Expr {
+	return f.term(">=", formatValue(value))
+}
+
+// LT matches documents where the field is less than value.
+func (f FieldRef) LT(value any) Expr {
+	return f.term("<", formatValue(value))
+}
+
+// LTE matches documents where the field is less than or equal to value.
+func (f FieldRef) LTE(value any) Expr {
+	return f.term("<=", formatValue(value))
+}
+
+// Between matches documents where the field lies within the inclusive
+// time range. A zero from or to leaves that side of the range open.
+func (f FieldRef) Between(from, to time.Time) Expr {
+	var exprs []Expr
+	if !from.IsZero() {
+		exprs = append(exprs, f.GTE(from))
+	}
+	if !to.IsZero() {
+		exprs = append(exprs, f.LTE(to))
+	}
+	return And(exprs...)
+}
+
+// Wildcard matches documents where the field matches pattern. The
+// wildcards * (any sequence) and ? (any character) are kept; all other
+// reserved characters are escaped.
+func (f FieldRef) Wildcard(pattern string) Expr {
+	var b strings.Builder
+	for _, r := range pattern {
+		switch {
+		case r == '*' || r == '?':
+			b.WriteRune(r)
+		case isReserved(r):
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return f.term("", b.String())
+}
+
+// Prefix matches documents where the field starts with prefix.
+func (f FieldRef) Prefix(prefix string) Expr {
+	return f.term("", escape(prefix)+"*")
+}
+
+// Exists matches documents where the field has any value.
+func (f FieldRef) Exists() Expr {
+	return f.term("", "*")
+}
+
+func (f FieldRef) term(op, value string) Expr {
+	return term(escape(f.name) + ":" + op + value)
+}
+
+// reservedChars are characters with special meaning in query syntax.
+const reservedChars = `+-&|!(){}[]^"~*?:\/<>= `
+
+func isReserved(r rune) bool {
+	return strings.ContainsRune(reservedChars, r) || unicode.IsSpace(r)
+}
+
+// escape backslash-escapes every reserved character in s.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if isReserved(r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// quote wraps s in double quotes, escaping backslashes and quotes.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// formatValue renders a value. Strings and times are quoted; numbers and
+// booleans are written as-is. Named types are rendered by their underlying
+// kind, so typed constants such as severities and statuses work directly.
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return `""`
+	case string:
+		return quote(v)
+	case time.Time:
+		return quote(v.UTC().Format(time.RFC3339))
+	case *time.Time:
+		if v == nil {
+			return `""`
+		}
+		return quote(v.UTC().Format(time.RFC3339))
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return quote(rv.String())
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	default:
+		return quote(fmt.Sprint(value))
+	}
+}
@@ -0,0 +1,111 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tphakala/go-xsoar"
+	"github.com/tphakala/go-xsoar/query"
+)
+
+func TestField(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     query.Expr
+		expected string
+	}{
+		{"string value is quoted", query.Field("owner").Eq("Jane Doe"), `owner:"Jane Doe"`},
+		{"quotes are escaped", query.Field("name").Eq(`say "hi" \o/`), `name:"say \"hi\" \\o/"`},
+		{"typed string constant", query.Field("status").Eq(xsoar.StatusActive), `status:"Active"`},
+		{"typed int constant", query.Field("severity").GTE(xsoar.SeverityHigh), `severity:>=4`},
+		{"number", query.Field("severity").GT(2), `severity:>2`},
+		{"float", query.Field("score").LT(2.5), `score:<2.5`},
+		{"bool", query.Field("isPlayground").Eq(false), `isPlayground:false`},
+		{"time", query.Field("created").LTE(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)), `created:<="2024-01-02T03:04:05Z"`},
+		{"field name is escaped", query.Field("my field:x").Eq(1), `my\ field\:x:1`},
+		{"field name keeps dots", query.Field("CustomFields.host").Eq("a"), `CustomFields.host:"a"`},
+		{"wildcard keeps * and ?", query.Field("name").Wildcard("phish* (v?)"), `name:phish*\ \(v?\)`},
+		{"prefix escapes value", query.Field("name").Prefix("a*b"), `name:a\*b*`},
+		{"exists", query.Field("owner").Exists(), `owner:*`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.expr.String())
+		})
+	}
+}
+
+func TestCustomField(t *testing.T) {
+	assert.Equal(t, `affectedhost:"srv-1"`, query.CustomField("Affected Host").Eq("srv-1").String())
+	assert.Equal(t, `affectedhost:"srv-1"`, query.CustomField("affected.host").Eq("srv-1").String())
+}
+
+func TestBetween(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t,
+		`created:>="2024-01-01T00:00:00Z" and created:<="2024-02-01T00:00:00Z"`,
+		query.Field("created").Between(from, to).String())
+	assert.Equal(t, `created:>="2024-01-01T00:00:00Z"`, query.Field("created").Between(from, time.Time{}).String())
+	assert.Empty(t, query.Field("created").Between(time.Time{}, time.Time{}).String())
+}
+
+func TestBooleanOperators(t *testing.T) {
+	t.Run("and", func(t *testing.T) {
+		q := query.And(
+			query.Field("status").Eq("Active"),
+			query.Field("severity").GTE(3),
+		)
+		assert.Equal(t, `status:"Active" and severity:>=3`, q.String())
+	})
+
+	t.Run("nested groups are parenthesized", func(t *testing.T) {
+		q := query.And(
+			query.Field("type").Eq("Phishing"),
+			query.Or(query.Field("owner").Eq("a"), query.Field("owner").Eq("b")),
+		)
+		assert.Equal(t, `type:"Phishing" and (owner:"a" or owner:"b")`, q.String())
+	})
+
+	t.Run("in", func(t *testing.T) {
+		q := query.And(query.Field("severity").In(3, 4), query.Field("status").Eq("Active"))
+		assert.Equal(t, `(severity:3 or severity:4) and status:"Active"`, q.String())
+	})
+
+	t.Run("not", func(t *testing.T) {
+		assert.Equal(t, `-owner:""`, query.Not(query.Field("owner").Eq("")).String())
+		assert.Equal(t, `-(owner:"a" or owner:"b")`, query.Not(query.Field("owner").In("a", "b")).String())
+	})
+
+	t.Run("empty expressions are skipped", func(t *testing.T) {
+		q := query.And(nil, query.And(), query.Field("status").Eq("Active"), query.Or())
+		assert.Equal(t, `status:"Active"`, q.String())
+	})
+
+	t.Run("in without values matches nothing", func(t *testing.T) {
+		q := query.And(query.Field("status").Eq("Active"), query.Field("owner").In())
+		assert.Equal(t, `status:"Active" and -id:*`, q.String())
+		assert.Equal(t, `id:*`, query.Not(query.Field("owner").In()).String())
+		assert.Equal(t, `id:*`, query.Not(query.Or(query.Field("owner").In())).String())
+	})
+
+	t.Run("negated empty expressions match nothing", func(t *testing.T) {
+		assert.Equal(t, `-id:*`, query.Not(query.Or()).String())
+		assert.Equal(t, `-id:*`, query.Not(nil).String())
+		q := query.Or(query.Field("owner").Eq("a"), query.Not(query.And()))
+		assert.Equal(t, `owner:"a" or -id:*`, q.String())
+	})
+
+	t.Run("single-item groups are not parenthesized", func(t *testing.T) {
+		q := query.And(query.Or(query.Field("owner").Eq("a")), query.Field("status").Eq("Active"))
+		assert.Equal(t, `owner:"a" and status:"Active"`, q.String())
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		q := query.Or(query.Raw(`status:Active`), query.Field("owner").Eq("a"))
+		assert.Equal(t, `status:Active or owner:"a"`, q.String())
+	})
+}
@@ -0,0 +1,104 @@
+package xsoar
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// doRequest executes an API request, unmarshals a successful JSON response
+// into result, and converts error responses into typed errors.
+func doRequest(ctx context.Context, t *api.Transport, req *api.Request, result any) error {
+	resp, err := t.DoJSON(ctx, req, result)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return parseError(resp.StatusCode, resp.Body, resp.Headers)
+	}
+
+	return nil
+}
+
+// doStream executes an API request and returns the unread body of a
+// successful response. The caller must close the returned reader.
+func doStream(ctx context.Context, t *api.Transport, req *api.Request) (io.ReadCloser, error) {
+	resp, err := t.DoStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, parseError(resp.StatusCode, resp.Body, resp.Headers)
+	}
+
+	return resp.Stream, nil
+}
+
+// formFile is a file part of a multipart request.
+type formFile struct {
+	field    string
+	filename string
+	content  io.Reader
+}
+
+// multipartBody streams a multipart form with the given fields and file
+// without buffering the file in memory. It returns the body and its content
+// type. The caller must close the body once the request has completed so
+// that the writer goroutine exits even if the body was never read.
+func multipartBody(fields map[string]string, file *formFile) (io.ReadCloser, string) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	go func() {
+		pw.CloseWithError(writeMultipart(mw, fields, file))
+	}()
+
+	return pr, mw.FormDataContentType()
+}
+
+func writeMultipart(mw *multipart.Writer, fields map[string]string, file *formFile) error {
+	for name, value := range fields {
+		if err := mw.WriteField(name, value); err != nil {
+			return err
+		}
+	}
+
+	if file != nil {
+		part, err := mw.CreateFormFile(file.field, file.filename)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, file.content); err != nil {
+			return err
+		}
+	}
+
+	return mw.Close()
+}
+
+// withResource annotates a NotFoundError with the resource that was requested.
+// Other errors are returned unchanged.
+func withResource(err error, resourceType, resourceID string) error {
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		notFound.ResourceType = resourceType
+		notFound.ResourceID = resourceID
+	}
+	return err
+}
+
+// validateRequired checks that a required string argument is not empty.
+func validateRequired(name, value string) error {
+	if value == "" {
+		return &ValidationError{
+			APIError: APIError{Message: name + " cannot be empty"},
+		}
+	}
+	return nil
+}
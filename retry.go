@@ -0,0 +1,70 @@
+package xsoar
+
+import (
+	"time"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// Default retry configuration values.
+const (
+	defaultMaxRetries     = 3
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// RetryPolicy configures automatic retries of failed API calls.
+//
+// Rate limited responses (429) are retried after the server-provided
+// Retry-After delay. Server errors (5xx) and transient network errors are
+// retried with jittered exponential backoff. Retries never outlive the
+// request context: if the next delay would exceed the context deadline,
+// the last error is returned immediately.
+//
+// Only idempotent calls are retried. Read operations (Get, Search) are
+// always eligible; mutating calls must opt in with WithIdempotent.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the first attempt.
+	MaxRetries int
+
+	// InitialBackoff is the base delay before the first retry.
+	// It doubles with each subsequent attempt.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the exponential delay between attempts.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with sensible defaults:
+// 3 retries, starting at 500ms and capped at 30s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     defaultMaxRetries,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+	}
+}
+
+// toAPI converts the policy to its transport representation,
+// filling unset durations with defaults.
+func (p *RetryPolicy) toAPI() *api.RetryPolicy {
+	if p == nil || p.MaxRetries <= 0 {
+		return nil
+	}
+
+	policy := &api.RetryPolicy{
+		MaxRetries:     p.MaxRetries,
+		InitialBackoff: p.InitialBackoff,
+		MaxBackoff:     p.MaxBackoff,
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = defaultInitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaultMaxBackoff
+	}
+	if policy.MaxBackoff < policy.InitialBackoff {
+		policy.MaxBackoff = policy.InitialBackoff
+	}
+	return policy
+}
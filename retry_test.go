@@ -0,0 +1,167 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+func setupRetryTestServer(t *testing.T, handler http.HandlerFunc) *xsoar.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := xsoar.NewClient(
+		xsoar.WithBaseURL(server.URL),
+		xsoar.WithAPIKey("test-key-id", "test-api-key"),
+		xsoar.WithRetryPolicy(xsoar.RetryPolicy{
+			MaxRetries:     3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     5 * time.Millisecond,
+		}),
+	)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("retries server errors until success", func(t *testing.T) {
+		var calls atomic.Int32
+		client := setupRetryTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			err := json.NewEncoder(w).Encode(xsoar.IncidentPage{Data: []*xsoar.Incident{{ID: "inc-1"}}, Total: 1})
+			assert.NoError(t, err)
+		})
+
+		page, err := client.Incidents.SearchPage(context.Background(), nil, nil)
+		require.NoError(t, err)
+		assert.Len(t, page.Data, 1)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("retries rate limited requests", func(t *testing.T) {
+		var calls atomic.Int32
+		client := setupRetryTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			err := json.NewEncoder(w).Encode(xsoar.Incident{ID: "inc-1"})
+			assert.NoError(t, err)
+		})
+
+		incident, err := client.Incidents.Get(context.Background(), "inc-1")
+		require.NoError(t, err)
+		assert.Equal(t, "inc-1", incident.ID)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		var calls atomic.Int32
+		client := setupRetryTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		})
+
+		_, err := client.Incidents.Get(context.Background(), "inc-1")
+		require.Error(t, err)
+
+		var serverErr *xsoar.ServerError
+		require.ErrorAs(t, err, &serverErr)
+		assert.Equal(t, int32(4), calls.Load())
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var calls atomic.Int32
+		client := setupRetryTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+		})
+
+		_, err := client.Incidents.SearchPage(context.Background(), nil, nil)
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("does not retry non-idempotent calls", func(t *testing.T) {
+		var calls atomic.Int32
+		client := setupRetryTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		_, err := client.Incidents.Create(context.Background(), &xsoar.CreateIncidentRequest{
+			Name: "Test",
+			Type: "Malware",
+		})
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("does not retry instance creation", func(t *testing.T) {
+		var calls atomic.Int32
+		client := setupRetryTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		_, err := client.Integrations.CreateInstance(context.Background(), &xsoar.IntegrationInstance{
+			Name:  "VT_new",
+			Brand: "VirusTotal",
+		})
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("retries calls marked idempotent", func(t *testing.T) {
+		var calls atomic.Int32
+		client := setupRetryTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		})
+
+		severity := xsoar.SeverityHigh
+		err := client.Incidents.Update(context.Background(), "inc-1", &xsoar.UpdateIncidentRequest{
+			Severity: &severity,
+		}, xsoar.WithIdempotent())
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("stops when retry-after exceeds context deadline", func(t *testing.T) {
+		var calls atomic.Int32
+		client := setupRetryTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		start := time.Now()
+		_, err := client.Incidents.Get(ctx, "inc-1")
+		require.Error(t, err)
+
+		var rateLimitErr *xsoar.RateLimitError
+		require.ErrorAs(t, err, &rateLimitErr)
+		assert.Equal(t, 60*time.Second, rateLimitErr.RetryAfter)
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
@@ -0,0 +1,159 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// ScriptService provides operations on automation scripts.
+//
+//go:generate mockery --name=ScriptService --output=mocks --outpkg=mocks --filename=script_service.go
+type ScriptService interface {
+	// Search returns an iterator over all automations matching the filter.
+	// A nil filter matches all automations. Results do not include code.
+	Search(ctx context.Context, filter *ScriptFilter, opts ...RequestOption) iter.Seq2[*Script, error]
+
+	// Get retrieves an automation by ID, including its code.
+	Get(ctx context.Context, id string, opts ...RequestOption) (*Script, error)
+
+	// Save creates an automation, or updates it if script.ID is set.
+	// If script.Version is set, the server rejects the write with a
+	// ConflictError when the automation has been modified since.
+	Save(ctx context.Context, script *Script, opts ...RequestOption) (*Script, error)
+
+	// Delete removes an automation by ID.
+	Delete(ctx context.Context, id string, opts ...RequestOption) error
+}
+
+// scriptService implements ScriptService.
+type scriptService struct {
+	transport *api.Transport
+}
+
+func newScriptService(transport *api.Transport) *scriptService {
+	return &scriptService{transport: transport}
+}
+
+// Search returns an iterator over all automations matching the filter.
+func (s *scriptService) Search(ctx context.Context, filter *ScriptFilter, opts ...RequestOption) iter.Seq2[*Script, error] {
+	return func(yield func(*Script, error) bool) {
+		reqCfg := newRequestConfig()
+		reqCfg.apply(opts...)
+
+		body := &scriptSearchRequest{
+			ScriptFilter: filter,
+			Size:         reqCfg.pageLimit(),
+		}
+		seen := 0
+
+		for {
+			var result scriptSearchResponse
+			err := doRequest(ctx, s.transport, &api.Request{
+				Method:     http.MethodPost,
+				Path:       "/automation/search",
+				Body:       body,
+				Headers:    reqCfg.headers,
+				Idempotent: true,
+			}, &result)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !yieldItems(ctx, result.Scripts, yield) {
+				return
+			}
+
+			seen += len(result.Scripts)
+			if len(result.Scripts) < body.Size || seen >= result.Total {
+				return
+			}
+			body.Page++
+		}
+	}
+}
+
+// Get retrieves an automation by ID.
+func (s *scriptService) Get(ctx context.Context, id string, opts ...RequestOption) (*Script, error) {
+	if err := validateRequired("script ID", id); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result Script
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       fmt.Sprintf("/automation/load/%s", url.PathEscape(id)),
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, &result)
+	if err != nil {
+		return nil, withResource(err, "script", id)
+	}
+
+	return &result, nil
+}
+
+// Save creates or updates an automation.
+func (s *scriptService) Save(ctx context.Context, script *Script, opts ...RequestOption) (*Script, error) {
+	if err := validateScript(script); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result Script
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/automation",
+		Body:       map[string]any{"script": script},
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Delete removes an automation by ID.
+func (s *scriptService) Delete(ctx context.Context, id string, opts ...RequestOption) error {
+	if err := validateRequired("script ID", id); err != nil {
+		return err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodPost,
+		Path:       "/automation/delete",
+		Body:       map[string]any{"script": map[string]string{"id": id}},
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, nil)
+	return withResource(err, "script", id)
+}
+
+// validateScript checks that a script has the fields required to save it.
+func validateScript(script *Script) error {
+	if script == nil {
+		return &ValidationError{APIError: APIError{Message: "script cannot be nil"}}
+	}
+	if err := validateRequired("script name", script.Name); err != nil {
+		return err
+	}
+	if err := validateRequired("script type", string(script.Type)); err != nil {
+		return err
+	}
+	return validateRequired("script code", script.Code)
+}
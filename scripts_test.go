@@ -0,0 +1,140 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+func TestScriptService_Search(t *testing.T) {
+	callCount := 0
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		assert.Equal(t, "/automation/search", r.URL.Path)
+
+		var reqBody map[string]any
+		err := json.NewDecoder(r.Body).Decode(&reqBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "tags:enrichment", reqBody["query"])
+
+		err = json.NewEncoder(w).Encode(map[string]any{
+			"scripts": []*xsoar.Script{
+				{ID: "s-1", Name: "EnrichIP", Type: xsoar.ScriptTypePython},
+				{ID: "s-2", Name: "EnrichHost", Type: xsoar.ScriptTypePowerShell},
+			},
+			"total": 2,
+		})
+		assert.NoError(t, err)
+	})
+
+	ctx := context.Background()
+	scripts, err := xsoar.Collect(client.Scripts.Search(ctx, &xsoar.ScriptFilter{Query: "tags:enrichment"}))
+	require.NoError(t, err)
+
+	assert.Len(t, scripts, 2)
+	assert.Equal(t, xsoar.ScriptTypePowerShell, scripts[1].Type)
+	assert.Equal(t, 1, callCount)
+}
+
+func TestScriptService_Get(t *testing.T) {
+	t.Run("decodes code, arguments and outputs", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/automation/load/s-1", r.URL.Path)
+
+			_, err := w.Write([]byte(`{
+				"id": "s-1", "name": "EnrichIP", "type": "python", "subtype": "python3",
+				"dockerImage": "demisto/python3:3.11", "tags": ["enrichment"], "version": 2,
+				"script": "print('hi')",
+				"arguments": [{"name": "ip", "required": true, "default": true, "isArray": true}],
+				"outputs": [{"contextPath": "IP.Address", "type": "string"}]
+			}`))
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		script, err := client.Scripts.Get(ctx, "s-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, "print('hi')", script.Code)
+		assert.Equal(t, "demisto/python3:3.11", script.DockerImage)
+		assert.Equal(t, "python3", script.Subtype)
+		require.Len(t, script.Arguments, 1)
+		assert.True(t, script.Arguments[0].Required)
+		assert.True(t, script.Arguments[0].IsArray)
+		assert.Equal(t, "IP.Address", script.Outputs[0].ContextPath)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		ctx := context.Background()
+		_, err := client.Scripts.Get(ctx, "missing")
+		var notFoundErr *xsoar.NotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "script", notFoundErr.ResourceType)
+	})
+}
+
+func TestScriptService_Save(t *testing.T) {
+	t.Run("wraps script in request body", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/automation", r.URL.Path)
+
+			var reqBody struct {
+				Script xsoar.Script `json:"script"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			assert.NoError(t, err)
+			assert.Equal(t, "EnrichIP", reqBody.Script.Name)
+			assert.Equal(t, "print('hi')", reqBody.Script.Code)
+
+			reqBody.Script.ID = "s-1"
+			err = json.NewEncoder(w).Encode(reqBody.Script)
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		script, err := client.Scripts.Save(ctx, &xsoar.Script{
+			Name: "EnrichIP",
+			Type: xsoar.ScriptTypePython,
+			Code: "print('hi')",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "s-1", script.ID)
+	})
+
+	t.Run("validates required fields", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call for an invalid script")
+		})
+
+		ctx := context.Background()
+		_, err := client.Scripts.Save(ctx, &xsoar.Script{Name: "EnrichIP", Type: xsoar.ScriptTypePython})
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "script code cannot be empty", validationErr.Message)
+	})
+}
+
+func TestScriptService_Delete(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/automation/delete", r.URL.Path)
+
+		var reqBody map[string]map[string]any
+		err := json.NewDecoder(r.Body).Decode(&reqBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "s-1", reqBody["script"]["id"])
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ctx := context.Background()
+	err := client.Scripts.Delete(ctx, "s-1")
+	require.NoError(t, err)
+}
@@ -0,0 +1,172 @@
+package xsoar
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/tphakala/go-xsoar/internal/api"
+)
+
+// TaskService provides operations on playbook tasks.
+//
+//go:generate mockery --name=TaskService --output=mocks --outpkg=mocks --filename=task_service.go
+type TaskService interface {
+	// List returns the tasks in an investigation's work plan, including
+	// the tasks of sub-playbooks, ordered by task ID.
+	List(ctx context.Context, investigationID string, opts ...RequestOption) ([]*Task, error)
+
+	// Complete completes a task with an optional answer and comment.
+	// For conditional manual tasks the answer selects the branch to take.
+	Complete(ctx context.Context, investigationID, taskID string, completion *TaskCompletion, opts ...RequestOption) error
+
+	// Assign assigns a task to a user.
+	Assign(ctx context.Context, investigationID, taskID, assignee string, opts ...RequestOption) error
+
+	// SetDueDate sets the due date of a task.
+	SetDueDate(ctx context.Context, investigationID, taskID string, due time.Time, opts ...RequestOption) error
+}
+
+// taskService implements TaskService.
+type taskService struct {
+	transport *api.Transport
+}
+
+func newTaskService(transport *api.Transport) *taskService {
+	return &taskService{transport: transport}
+}
+
+// List returns the tasks in an investigation's work plan.
+func (s *taskService) List(ctx context.Context, investigationID string, opts ...RequestOption) ([]*Task, error) {
+	if err := validateRequired("investigation ID", investigationID); err != nil {
+		return nil, err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	var result workplanResponse
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:     http.MethodGet,
+		Path:       fmt.Sprintf("/investigation/%s/workplan", url.PathEscape(investigationID)),
+		Headers:    reqCfg.headers,
+		Idempotent: true,
+	}, &result)
+	if err != nil {
+		return nil, withResource(err, "investigation", investigationID)
+	}
+
+	tasks := flattenTasks(nil, result.Playbook)
+	slices.SortFunc(tasks, func(a, b *Task) int {
+		// Task IDs are numeric strings; order "2" before "10".
+		return cmp.Or(cmp.Compare(len(a.ID), len(b.ID)), cmp.Compare(a.ID, b.ID))
+	})
+	return tasks, nil
+}
+
+// flattenTasks appends the tasks of a playbook and its sub-playbooks.
+func flattenTasks(tasks []*Task, playbook *workplanPlaybook) []*Task {
+	if playbook == nil {
+		return tasks
+	}
+	for _, task := range playbook.Tasks {
+		tasks = append(tasks, &task.Task)
+		tasks = flattenTasks(tasks, task.SubPlaybook)
+	}
+	return tasks
+}
+
+// Complete completes a task.
+func (s *taskService) Complete(ctx context.Context, investigationID, taskID string, completion *TaskCompletion, opts ...RequestOption) error {
+	if err := validateTaskRef(investigationID, taskID); err != nil {
+		return err
+	}
+	if completion == nil {
+		completion = &TaskCompletion{}
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	// The endpoint takes a multipart form so that a file can be attached.
+	body, contentType := multipartBody(map[string]string{
+		"investigationId": investigationID,
+		"taskId":          taskID,
+		"taskInput":       completion.Answer,
+		"taskComment":     completion.Comment,
+	}, nil)
+	defer func() { _ = body.Close() }()
+
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method:      http.MethodPost,
+		Path:        "/inv-playbook/task/complete",
+		RawBody:     body,
+		ContentType: contentType,
+		Headers:     reqCfg.headers,
+	}, nil)
+	return withResource(err, "task", taskID)
+}
+
+// Assign assigns a task to a user.
+func (s *taskService) Assign(ctx context.Context, investigationID, taskID, assignee string, opts ...RequestOption) error {
+	if err := validateTaskRef(investigationID, taskID); err != nil {
+		return err
+	}
+	if err := validateRequired("assignee", assignee); err != nil {
+		return err
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method: http.MethodPost,
+		Path:   "/inv-playbook/task/assign",
+		Body: map[string]any{
+			"investigationId": investigationID,
+			"taskId":          taskID,
+			"assignee":        assignee,
+		},
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, nil)
+	return withResource(err, "task", taskID)
+}
+
+// SetDueDate sets the due date of a task.
+func (s *taskService) SetDueDate(ctx context.Context, investigationID, taskID string, due time.Time, opts ...RequestOption) error {
+	if err := validateTaskRef(investigationID, taskID); err != nil {
+		return err
+	}
+	if due.IsZero() {
+		return &ValidationError{APIError: APIError{Message: "due date cannot be empty"}}
+	}
+
+	reqCfg := newRequestConfig()
+	reqCfg.apply(opts...)
+
+	err := doRequest(ctx, s.transport, &api.Request{
+		Method: http.MethodPost,
+		Path:   "/inv-playbook/task/due",
+		Body: map[string]any{
+			"investigationId": investigationID,
+			"taskId":          taskID,
+			"dueDate":         due.UTC(),
+		},
+		Headers:    reqCfg.headers,
+		Idempotent: reqCfg.idempotent,
+	}, nil)
+	return withResource(err, "task", taskID)
+}
+
+// validateTaskRef checks the arguments identifying a task.
+func validateTaskRef(investigationID, taskID string) error {
+	if err := validateRequired("investigation ID", investigationID); err != nil {
+		return err
+	}
+	return validateRequired("task ID", taskID)
+}
@@ -0,0 +1,146 @@
+package xsoar_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/go-xsoar"
+)
+
+func TestTaskService_List(t *testing.T) {
+	t.Run("flattens sub-playbooks in task order", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/investigation/inv-1/workplan", r.URL.Path)
+
+			_, err := w.Write([]byte(`{"invPlaybook": {"tasks": {
+				"10": {"id": "10", "type": "regular", "state": "Waiting",
+					"task": {"name": "Approve block"}, "assignee": "analyst",
+					"dueDate": "2026-01-02T00:00:00Z"},
+				"2": {"id": "2", "type": "playbook", "state": "inprogress",
+					"task": {"name": "Enrich", "playbookId": "Enrichment"},
+					"subPlaybook": {"tasks": {
+						"3": {"id": "3", "type": "regular", "state": "Completed",
+							"task": {"name": "Lookup", "scriptId": "ip"},
+							"parentPlaybookID": "2"}
+					}}},
+				"1": {"id": "1", "type": "start", "state": "Completed", "task": {"name": ""}}
+			}}}`))
+			assert.NoError(t, err)
+		})
+
+		ctx := context.Background()
+		tasks, err := client.Tasks.List(ctx, "inv-1")
+		require.NoError(t, err)
+
+		require.Len(t, tasks, 4)
+		ids := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		assert.Equal(t, []string{"1", "2", "3", "10"}, ids)
+
+		approval := tasks[3]
+		assert.Equal(t, "Approve block", approval.Definition.Name)
+		assert.Equal(t, xsoar.TaskStateWaiting, approval.State)
+		assert.Equal(t, "analyst", approval.Assignee)
+		assert.Equal(t, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), approval.DueDate)
+		assert.True(t, approval.IsManual())
+
+		assert.Equal(t, "2", tasks[2].ParentPlaybookID)
+		assert.False(t, tasks[2].IsManual())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		ctx := context.Background()
+		_, err := client.Tasks.List(ctx, "inv-404")
+		var notFoundErr *xsoar.NotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "investigation", notFoundErr.ResourceType)
+	})
+}
+
+func TestTaskService_Complete(t *testing.T) {
+	t.Run("sends answer and comment as form fields", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/inv-playbook/task/complete", r.URL.Path)
+
+			err := r.ParseMultipartForm(1 << 20)
+			assert.NoError(t, err)
+			assert.Equal(t, "inv-1", r.FormValue("investigationId"))
+			assert.Equal(t, "10", r.FormValue("taskId"))
+			assert.Equal(t, "Yes", r.FormValue("taskInput"))
+			assert.Equal(t, "approved in chat", r.FormValue("taskComment"))
+			w.WriteHeader(http.StatusOK)
+		})
+
+		ctx := context.Background()
+		err := client.Tasks.Complete(ctx, "inv-1", "10", &xsoar.TaskCompletion{
+			Answer:  "Yes",
+			Comment: "approved in chat",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("validates task reference", func(t *testing.T) {
+		client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("should not make API call without a task ID")
+		})
+
+		ctx := context.Background()
+		err := client.Tasks.Complete(ctx, "inv-1", "", nil)
+		var validationErr *xsoar.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "task ID cannot be empty", validationErr.Message)
+	})
+}
+
+func TestTaskService_Assign(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/inv-playbook/task/assign", r.URL.Path)
+
+		var reqBody map[string]any
+		err := json.NewDecoder(r.Body).Decode(&reqBody)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"investigationId": "inv-1", "taskId": "10", "assignee": "analyst"}, reqBody)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ctx := context.Background()
+	err := client.Tasks.Assign(ctx, "inv-1", "10", "analyst")
+	require.NoError(t, err)
+
+	err = client.Tasks.Assign(ctx, "inv-1", "10", "")
+	var validationErr *xsoar.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func TestTaskService_SetDueDate(t *testing.T) {
+	client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/inv-playbook/task/due", r.URL.Path)
+
+		var reqBody map[string]any
+		err := json.NewDecoder(r.Body).Decode(&reqBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "2026-01-02T15:00:00Z", reqBody["dueDate"])
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ctx := context.Background()
+	due := time.Date(2026, 1, 2, 16, 0, 0, 0, time.FixedZone("CET", 3600))
+	err := client.Tasks.SetDueDate(ctx, "inv-1", "10", due)
+	require.NoError(t, err)
+
+	err = client.Tasks.SetDueDate(ctx, "inv-1", "10", time.Time{})
+	var validationErr *xsoar.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+}